@@ -0,0 +1,120 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// WriteAssertions replaces the assertions stored with the model modelID, or
+// with the active model when modelID is empty. Assertions are checked
+// against the model first: every one naming an undefined type or relation
+// is reported in a single *TupleValidationError and nothing is written.
+func (c *Client) WriteAssertions(ctx context.Context, modelID string, assertions []client.ClientAssertion) error {
+	m, err := c.assertionModel(ctx, modelID)
+	if err != nil {
+		return err
+	}
+	idx := indexTypeDefs(m.TypeDefinitions)
+	values := make([]string, 0, 2*len(assertions))
+	for _, a := range assertions {
+		values = append(values, a.User, a.Object)
+	}
+	var verr TupleValidationError
+	for i, a := range assertions {
+		tk := client.ClientTupleKey{User: a.User, Relation: a.Relation, Object: a.Object}
+		if reason := idx.checkQuery(tk); reason != "" {
+			verr.Problems = append(verr.Problems, TupleProblem{Index: i, Tuple: tk, Reason: reason})
+		}
+	}
+	if len(verr.Problems) > 0 {
+		return c.redactErr(&verr, values...)
+	}
+
+	err = c.call(ctx, "WriteAssertions", func(ctx context.Context) error {
+		_, err := c.fga.WriteAssertions(ctx, assertions, client.ClientWriteAssertionsOptions{
+			AuthorizationModelId: &m.Id,
+			StoreId:              &c.StoreID,
+		})
+		return c.redactErr(err, values...)
+	})
+	if err != nil {
+		return fmt.Errorf("write assertions: %w", err)
+	}
+	return nil
+}
+
+// ReadAssertions returns the assertions stored with the model modelID, or
+// with the active model when modelID is empty.
+func (c *Client) ReadAssertions(ctx context.Context, modelID string) ([]client.ClientAssertion, error) {
+	if modelID == "" {
+		id, err := c.resolveModel(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if id == nil {
+			return nil, fmt.Errorf("authz: store %s has no authorization model", c.StoreID)
+		}
+		modelID = *id
+	}
+	var resp *client.ClientReadAssertionsResponse
+	err := c.call(ctx, "ReadAssertions", func(ctx context.Context) (err error) {
+		resp, err = c.fga.ReadAssertions(ctx, client.ClientReadAssertionsOptions{
+			AuthorizationModelId: &modelID,
+			StoreId:              &c.StoreID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read assertions: %w", err)
+	}
+	var out []client.ClientAssertion
+	for _, a := range resp.GetAssertions() {
+		out = append(out, client.ClientAssertion{
+			User:        a.TupleKey.User,
+			Relation:    a.TupleKey.Relation,
+			Object:      a.TupleKey.Object,
+			Expectation: a.Expectation,
+		})
+	}
+	return out, nil
+}
+
+func (c *Client) assertionModel(ctx context.Context, modelID string) (*openfga.AuthorizationModel, error) {
+	if modelID == "" {
+		return c.ActiveModel(ctx)
+	}
+	return c.readModel(ctx, modelID)
+}
+
+// ModelTestAssertions converts the check assertions of the .fga.yaml test
+// file at path into server-side assertions, in file order, so the same
+// cases run locally with RunModelTests can be uploaded with
+// WriteAssertions. Server assertions are evaluated against the store's own
+// tuples, so the file's tuples are not part of the result, and checks that
+// use context or contextual tuples cannot be expressed and are an error.
+func ModelTestAssertions(path string) ([]client.ClientAssertion, error) {
+	file, _, err := loadModelTestFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []client.ClientAssertion
+	for _, test := range file.Tests {
+		for _, chk := range test.Check {
+			if chk.Context != nil || len(chk.ContextualTuples) > 0 {
+				return nil, fmt.Errorf("model tests %s: test %q: check of %s on %s uses context or contextual tuples, which assertions do not support", path, test.Name, chk.User, chk.Object)
+			}
+			for _, rel := range sortedKeys(chk.Assertions) {
+				out = append(out, client.ClientAssertion{
+					User:        chk.User,
+					Relation:    rel,
+					Object:      chk.Object,
+					Expectation: chk.Assertions[rel],
+				})
+			}
+		}
+	}
+	return out, nil
+}
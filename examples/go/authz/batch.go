@@ -0,0 +1,303 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RelationObject names a relation on an object, e.g. {"viewer", "document:1"}.
+type RelationObject struct {
+	Relation string
+	Object   string
+}
+
+// Key returns the "relation:object" form used to index BatchCheck results.
+func (ro RelationObject) Key() string {
+	return ro.Relation + ":" + ro.Object
+}
+
+// BatchCheck checks user against every pair concurrently, with at most
+// Config.CheckConcurrency checks in flight. Results are keyed by
+// RelationObject.Key. The first failed check cancels the remaining ones and
+// its error is returned; cancelling ctx does the same. BatchCheckResults
+// instead reports failures per pair and uses the server's BatchCheck
+// endpoint where available.
+func (c *Client) BatchCheck(ctx context.Context, user string, pairs []RelationObject, opts ...QueryOption) (map[string]bool, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.checkConcurrency)
+
+	var mu sync.Mutex
+	results := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		pair := pair
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			allowed, err := c.Check(ctx, user, pair.Relation, pair.Object, opts...)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			results[pair.Key()] = allowed
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ListObjectsMulti lists the objects of objType on which user has each of
+// relations, e.g. to know in one step which documents a user can view,
+// edit and delete. Results are keyed by relation and hold objects as
+// ListObjects returns them; an object a user holds several relations on
+// appears under each. The calls run concurrently with at most
+// Config.CheckConcurrency in flight. The first failure or cancelling ctx
+// stops the rest and returns the error. A truncated result does not: every
+// relation is listed, and the error matches ErrResultTruncated.
+func (c *Client) ListObjectsMulti(ctx context.Context, user string, relations []string, objType string, opts ...QueryOption) (map[string][]string, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.checkConcurrency)
+
+	var (
+		mu        sync.Mutex
+		truncated error
+	)
+	results := make(map[string][]string, len(relations))
+	seen := make(map[string]bool, len(relations))
+	for _, relation := range relations {
+		relation := relation
+		if seen[relation] {
+			continue
+		}
+		seen[relation] = true
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			objects, err := c.ListObjects(gctx, user, relation, objType, opts...)
+			if err != nil && !errors.Is(err, ErrResultTruncated) {
+				return fmt.Errorf("%s: %w", relation, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[relation] = objects
+			if err != nil && truncated == nil {
+				truncated = fmt.Errorf("%s: %w", relation, err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, truncated
+}
+
+// Warm prefetches the checks of user against pairs into the Check cache,
+// so that the individual Checks a page render makes afterwards are cache
+// hits for up to CacheConfig.TTL. The checks run as BatchCheck does, and
+// always go to the server, so a warmed result starts a fresh TTL. opts must
+// match those of the later Checks for them to hit. Warm does nothing when
+// the cache is disabled.
+func (c *Client) Warm(ctx context.Context, user string, pairs []RelationObject, opts ...QueryOption) error {
+	if c.cache == nil {
+		return nil
+	}
+	refresh := func(p *queryParams) { p.refresh = true }
+	if _, err := c.BatchCheck(ctx, user, pairs, append([]QueryOption{refresh}, opts...)...); err != nil {
+		return fmt.Errorf("warm: %w", err)
+	}
+	return nil
+}
+
+// checkManyListUsersMin is the number of users from which CheckMany asks
+// ListUsers once instead of issuing one Check per user.
+const checkManyListUsersMin = 25
+
+// CheckMany reports which users hold relation on object, e.g. to render an
+// access list. Results are keyed by user. Checks run concurrently with at
+// most Config.CheckConcurrency in flight; the first failure or cancelling
+// ctx stops the rest and returns the error.
+//
+// When at least 25 users are given and all are concrete objects of a single
+// type ("user:..."), one ListUsers call answers for the users it returns.
+// The server caps and time-limits ListUsers results without saying so, so
+// the users it leaves out are still checked individually; a model where
+// most of the users hold relation then needs few Checks. If that call fails
+// for any reason other than ctx ending, CheckMany checks every user.
+func (c *Client) CheckMany(ctx context.Context, users []string, relation, object string, opts ...QueryOption) (map[string]bool, error) {
+	results := make(map[string]bool, len(users))
+	pending := users
+	if len(users) >= checkManyListUsersMin {
+		if userType, ok := commonUserType(users); ok {
+			res, err := c.ListUsers(ctx, object, relation, []string{userType}, opts...)
+			switch {
+			case err == nil:
+				pending = listUsersMembership(users, userType, res, results)
+			case ctx.Err() != nil:
+				return nil, ctx.Err()
+			default:
+				c.logger().Debug("ListUsers failed; falling back to checks", "operation", "CheckMany", "error", err)
+			}
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.checkConcurrency)
+
+	var mu sync.Mutex
+	for _, user := range pending {
+		user := user
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			allowed, err := c.Check(gctx, user, relation, object, opts...)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			results[user] = allowed
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// commonUserType returns the type shared by users if every one of them is
+// a concrete "type:id" object.
+func commonUserType(users []string) (string, bool) {
+	var userType string
+	for _, u := range users {
+		typ, id, ok := strings.Cut(u, ":")
+		if !ok || typ == "" || id == "" || id == "*" || strings.Contains(id, "#") {
+			return "", false
+		}
+		if userType != "" && typ != userType {
+			return "", false
+		}
+		userType = typ
+	}
+	return userType, userType != ""
+}
+
+// listUsersMembership records in results the users that res shows to hold
+// the relation, and returns the others, which res may have cut off.
+func listUsersMembership(users []string, userType string, res ListUsersResult, results map[string]bool) []string {
+	public := false
+	for _, w := range res.Wildcards {
+		if w == userType+":*" {
+			public = true
+		}
+	}
+	allowed := make(map[string]bool, len(res.Users))
+	for _, u := range res.Users {
+		allowed[u] = true
+	}
+	var missing []string
+	for _, u := range users {
+		if public || allowed[u] {
+			results[u] = true
+		} else {
+			missing = append(missing, u)
+		}
+	}
+	return missing
+}
+
+// ListObjectsFiltered returns the objects of objType on which user has
+// relation, answered by Checks rather than by ListObjects alone.
+//
+// With candidates, only those objects are checked, concurrently as in
+// BatchCheck, and ListObjects is not called: use it when the caller already
+// holds a short list to intersect with, such as the page of documents about
+// to be shown. N Checks are cheaper than one ListObjects when N is small
+// relative to the number of objects of objType the server would have to
+// walk, and they are cached by the Check cache, which ListObjects is not.
+//
+// With nil candidates, ListObjects runs first and each object it returns is
+// then confirmed with a Check, dropping any the Check denies. This costs one
+// Check per result on top of the ListObjects call, and suits models where
+// ListObjects may over-report; pass WithConsistency to confirm against the
+// latest tuples.
+//
+// Objects are returned in candidate (or ListObjects) order, without
+// duplicates. Every candidate must be an object of objType.
+func (c *Client) ListObjectsFiltered(ctx context.Context, user, relation, objType string, candidates []string, opts ...QueryOption) ([]string, error) {
+	if candidates == nil {
+		objects, err := c.ListObjects(ctx, user, relation, objType, opts...)
+		if err != nil {
+			return nil, err
+		}
+		candidates = objects
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	pairs := make([]RelationObject, 0, len(candidates))
+	for _, obj := range candidates {
+		if typ, id, ok := strings.Cut(obj, ":"); !ok || typ != objType || id == "" {
+			return nil, fmt.Errorf("authz: candidate %q is not an object of type %q", obj, objType)
+		}
+		if !seen[obj] {
+			seen[obj] = true
+			pairs = append(pairs, RelationObject{Relation: relation, Object: obj})
+		}
+	}
+	results, err := c.BatchCheck(ctx, user, pairs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if results[pair.Key()] {
+			allowed = append(allowed, pair.Object)
+		}
+	}
+	return allowed, nil
+}
+
+// ListRelations returns the relations, among those given, that user holds
+// on object, e.g. to show every permission on an object's details page.
+// The relations are checked concurrently as in BatchCheck, and the result
+// keeps their order. With nil relations every relation the object's type
+// defines in the active model is checked; see RelationsForType.
+func (c *Client) ListRelations(ctx context.Context, user, object string, relations []string, opts ...QueryOption) ([]string, error) {
+	if relations == nil {
+		objType, _, ok := strings.Cut(object, ":")
+		if !ok || objType == "" {
+			return nil, fmt.Errorf("authz: invalid object %q", object)
+		}
+		var err error
+		if relations, err = c.RelationsForType(ctx, objType); err != nil {
+			return nil, err
+		}
+	}
+	pairs := make([]RelationObject, len(relations))
+	for i, rel := range relations {
+		pairs[i] = RelationObject{Relation: rel, Object: object}
+	}
+	results, err := c.BatchCheck(ctx, user, pairs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	held := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if results[pair.Key()] {
+			held = append(held, pair.Relation)
+		}
+	}
+	return held, nil
+}
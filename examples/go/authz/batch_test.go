@@ -0,0 +1,96 @@
+package authz_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	"github.com/bogdanticu88/openfga-examples/authz/fake"
+	"github.com/openfga/go-sdk/client"
+)
+
+func BenchmarkBatchCheck(b *testing.B) {
+	pairs := make([]authz.RelationObject, 100)
+	for i := range pairs {
+		pairs[i] = authz.RelationObject{Relation: "viewer", Object: fmt.Sprintf("project:%d", i)}
+	}
+	for _, bc := range benchCaches {
+		b.Run(bc.name, func(b *testing.B) {
+			c := benchClient(b, bc.cache)
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				results, err := c.BatchCheck(ctx, "user:anne", pairs)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(results) != len(pairs) {
+					b.Fatalf("got %d results, want %d", len(results), len(pairs))
+				}
+			}
+		})
+	}
+}
+
+// truncatingFGA returns at most limit users from ListUsers, as a server
+// does past OPENFGA_LIST_USERS_MAX_RESULTS, and counts Checks.
+type truncatingFGA struct {
+	*fake.Client
+	limit  int
+	checks atomic.Int32
+}
+
+func (f *truncatingFGA) ListUsers(ctx context.Context, body client.ClientListUsersRequest, opts client.ClientListUsersOptions) (*client.ClientListUsersResponse, error) {
+	resp, err := f.Client.ListUsers(ctx, body, opts)
+	if err == nil && len(resp.Users) > f.limit {
+		resp.Users = resp.Users[:f.limit]
+	}
+	return resp, err
+}
+
+func (f *truncatingFGA) Check(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error) {
+	f.checks.Add(1)
+	return f.Client.Check(ctx, body, opts)
+}
+
+func TestCheckManyTruncatedListUsers(t *testing.T) {
+	ctx := context.Background()
+	fga := &truncatingFGA{Client: fake.New(), limit: 10}
+	c := authz.NewWithFGA(fga, authz.Config{})
+	defer c.Close()
+	if _, err := c.CreateStore(ctx, "checkmany"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.WriteModelFromFile(ctx, "../model.fga"); err != nil {
+		t.Fatal(err)
+	}
+	tuples := []client.ClientTupleKey{{User: "organization:acme#member", Relation: "viewer", Object: "project:apollo"}}
+	var users []string
+	for i := 0; i < 40; i++ {
+		user := fmt.Sprintf("user:%d", i)
+		users = append(users, user)
+		if i < 30 {
+			tuples = append(tuples, client.ClientTupleKey{User: user, Relation: "member", Object: "organization:acme"})
+		}
+	}
+	if err := c.Grant(ctx, tuples...); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := c.CheckMany(ctx, users, "viewer", "project:apollo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, user := range users {
+		if want := i < 30; results[user] != want {
+			t.Errorf("CheckMany[%s] = %v, want %v", user, results[user], want)
+		}
+	}
+	// The 10 users ListUsers returned need no Check; the other 30 do.
+	if got := fga.checks.Load(); got != 30 {
+		t.Errorf("CheckMany made %d Checks, want 30", got)
+	}
+}
@@ -0,0 +1,300 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// BatchChecker is implemented by FGA implementations that support the
+// server's BatchCheck endpoint, added in OpenFGA v1.8.0, which evaluates
+// several checks in one request. It returns one result per check, in order.
+// An implementation talking to a server without the endpoint returns an
+// error matching ErrBatchCheckUnsupported.
+type BatchChecker interface {
+	ServerBatchCheck(ctx context.Context, checks []client.ClientCheckRequest, opts client.ClientCheckOptions) ([]BatchResult, error)
+}
+
+// ErrBatchCheckUnsupported means the server has no BatchCheck endpoint.
+var ErrBatchCheckUnsupported = errors.New("authz: server does not support batch check")
+
+// MaxChecksPerBatch is the server's default limit on checks in one
+// BatchCheck request (OPENFGA_MAX_CHECKS_PER_BATCH_CHECK).
+const MaxChecksPerBatch = 50
+
+// BatchResult is the outcome of one check in BatchCheckResults.
+type BatchResult struct {
+	Allowed bool
+	// Err is set when this check failed; Allowed is then false. It matches
+	// ErrValidation when the server rejected the check as invalid.
+	Err error
+}
+
+// BatchCheckResults checks user against every pair, like BatchCheck, but
+// reports failures per pair: a pair the server cannot evaluate, e.g. one
+// naming an undefined relation, has its own BatchResult.Err and does not
+// fail the others. Results are keyed by RelationObject.Key.
+//
+// When the FGA implementation implements BatchChecker the pairs are sent in
+// requests of up to MaxChecksPerBatch checks, far cheaper than one Check
+// each. Otherwise, and against servers older than v1.8.0, each pair is
+// checked on its own as BatchCheck does, with at most
+// Config.CheckConcurrency checks in flight; a server without the endpoint
+// is remembered and not asked again. The gRPC transport always checks
+// pairs individually. Results cached by Config.CheckCache are used, and
+// fresh ones cached, just as for Check.
+//
+// The error is reserved for failures of the batch as a whole, such as a
+// malformed user, no authorization model, or a cancelled ctx. An empty user
+// defaults to the one attached to ctx by WithUser.
+func (c *Client) BatchCheckResults(ctx context.Context, user string, pairs []RelationObject, opts ...QueryOption) (_ map[string]BatchResult, err error) {
+	user = userOrContext(ctx, user)
+	ctx, span := c.startSpan(ctx, "BatchCheck", attribute.Int("fga.checks", len(pairs)))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := ParseUser(user); err != nil {
+		return nil, fmt.Errorf("batch check: %w", c.redactErr(err, user))
+	}
+	p := newQueryParams(opts)
+	modelID, err := c.queryModel(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	setSpanModel(span, modelID)
+
+	results := make(map[string]BatchResult, len(pairs))
+	var params string
+	cacheable := c.cache != nil && modelID != nil
+	if cacheable {
+		params, cacheable = p.paramsHash()
+	}
+	keyFor := func(pair RelationObject) checkKey {
+		return checkKey{store: c.StoreID, model: *modelID, user: user, relation: pair.Relation, object: pair.Object, params: params}
+	}
+	pending := make([]RelationObject, 0, len(pairs))
+	for _, pair := range pairs {
+		if _, dup := results[pair.Key()]; dup {
+			continue
+		}
+		if cacheable && !p.strong() && !p.refresh {
+			if allowed, ok := c.cache.get(keyFor(pair)); ok {
+				results[pair.Key()] = BatchResult{Allowed: allowed}
+				continue
+			}
+		}
+		// Reserve the key so that repeated pairs are checked once.
+		results[pair.Key()] = BatchResult{}
+		pending = append(pending, pair)
+	}
+
+	checked, err := c.batchCheckNative(ctx, user, pending, p, modelID)
+	if errors.Is(err, ErrBatchCheckUnsupported) {
+		checked, err = c.batchCheckEach(ctx, user, pending, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, pair := range pending {
+		results[pair.Key()] = checked[i]
+		if cacheable && checked[i].Err == nil {
+			c.cache.put(keyFor(pair), checked[i].Allowed)
+		}
+	}
+	return results, nil
+}
+
+// batchCheckNative evaluates pairs through the server's BatchCheck
+// endpoint, returning one result per pair in order. It returns an error
+// matching ErrBatchCheckUnsupported when the FGA implementation or the
+// server lacks the endpoint.
+func (c *Client) batchCheckNative(ctx context.Context, user string, pairs []RelationObject, p queryParams, modelID *string) ([]BatchResult, error) {
+	checker, ok := c.fga.(BatchChecker)
+	if !ok || c.noBatchCheck.Load() {
+		return nil, ErrBatchCheckUnsupported
+	}
+	checkOpts := client.ClientCheckOptions{
+		AuthorizationModelId: modelID,
+		StoreId:              &c.StoreID,
+		Consistency:          p.consistency,
+	}
+	results := make([]BatchResult, len(pairs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.checkConcurrency)
+	for start := 0; start < len(pairs); start += MaxChecksPerBatch {
+		chunk := pairs[start:min(start+MaxChecksPerBatch, len(pairs))]
+		out := results[start : start+len(chunk)]
+		g.Go(func() error {
+			checks := make([]client.ClientCheckRequest, len(chunk))
+			values := queryValues(p.contextualTuples, user)
+			for i, pair := range chunk {
+				checks[i] = client.ClientCheckRequest{
+					User:             user,
+					Relation:         pair.Relation,
+					Object:           pair.Object,
+					Context:          p.contextPtr(),
+					ContextualTuples: p.contextualTuples,
+				}
+				values = append(values, pair.Object)
+			}
+			var resp []BatchResult
+			err := c.call(gctx, "BatchCheck", func(ctx context.Context) (err error) {
+				resp, err = checker.ServerBatchCheck(ctx, checks, checkOpts)
+				return c.redactErr(err, values...)
+			})
+			if err != nil {
+				return err
+			}
+			if len(resp) != len(chunk) {
+				return fmt.Errorf("got %d results for %d checks", len(resp), len(chunk))
+			}
+			for i, r := range resp {
+				if r.Err != nil {
+					r = BatchResult{Err: c.redactErr(classifyError(r.Err), queryValues(p.contextualTuples, user, chunk[i].Object)...)}
+				}
+				out[i] = r
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+	if errors.Is(err, ErrBatchCheckUnsupported) {
+		if !c.noBatchCheck.Swap(true) {
+			c.logger().Info("server has no batch check endpoint; checking individually", "operation", "BatchCheck")
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("batch check: %w", err)
+	}
+	return results, nil
+}
+
+// batchCheckEach checks each pair on its own, recording failures per pair.
+// Only a cancelled ctx fails the whole batch.
+func (c *Client) batchCheckEach(ctx context.Context, user string, pairs []RelationObject, opts []QueryOption) ([]BatchResult, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.checkConcurrency)
+
+	var mu sync.Mutex
+	results := make([]BatchResult, len(pairs))
+	for i, pair := range pairs {
+		i, pair := i, pair
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			allowed, err := c.Check(gctx, user, pair.Relation, pair.Object, opts...)
+			if err != nil && gctx.Err() != nil {
+				return gctx.Err()
+			}
+			mu.Lock()
+			results[i] = BatchResult{Allowed: allowed, Err: err}
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("batch check: %w", err)
+	}
+	return results, nil
+}
+
+// ServerBatchCheck calls the BatchCheck endpoint directly, as the SDK does
+// not expose it. Each check is sent with its index as its correlation ID.
+func (s sdkFGA) ServerBatchCheck(ctx context.Context, checks []client.ClientCheckRequest, opts client.ClientCheckOptions) ([]BatchResult, error) {
+	type batchItem struct {
+		TupleKey         openfga.CheckRequestTupleKey `json:"tuple_key"`
+		ContextualTuples *openfga.ContextualTupleKeys `json:"contextual_tuples,omitempty"`
+		Context          *map[string]interface{}      `json:"context,omitempty"`
+		CorrelationID    string                       `json:"correlation_id"`
+	}
+	body := struct {
+		Checks               []batchItem                    `json:"checks"`
+		AuthorizationModelID *string                        `json:"authorization_model_id,omitempty"`
+		Consistency          *openfga.ConsistencyPreference `json:"consistency,omitempty"`
+	}{AuthorizationModelID: opts.AuthorizationModelId, Consistency: opts.Consistency}
+	for i, chk := range checks {
+		req := checkRequest(chk, opts)
+		body.Checks = append(body.Checks, batchItem{
+			TupleKey:         req.TupleKey,
+			ContextualTuples: req.ContextualTuples,
+			Context:          req.Context,
+			CorrelationID:    strconv.Itoa(i),
+		})
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimRight(s.url, "/") + "/stores/" + url.PathEscape(deref(opts.StoreId)) + "/batch-check"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr)
+		herr := &httpError{code: resp.StatusCode, msg: strings.TrimSpace(apiErr.Code + " " + apiErr.Message)}
+		// Servers before v1.8.0 have no such route. A missing store is
+		// also a 404, but names its error code.
+		if resp.StatusCode == http.StatusNotImplemented ||
+			resp.StatusCode == http.StatusNotFound && apiErr.Code == "" {
+			return nil, fmt.Errorf("%w: %w", ErrBatchCheckUnsupported, herr)
+		}
+		return nil, herr
+	}
+
+	var out struct {
+		Result map[string]struct {
+			Allowed bool `json:"allowed"`
+			Error   *struct {
+				InputError    json.RawMessage `json:"input_error"`
+				InternalError json.RawMessage `json:"internal_error"`
+				Message       string          `json:"message"`
+			} `json:"error"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode batch check: %w", err)
+	}
+	results := make([]BatchResult, len(checks))
+	for i := range checks {
+		r, ok := out.Result[strconv.Itoa(i)]
+		switch {
+		case !ok:
+			results[i].Err = &httpError{code: http.StatusInternalServerError, msg: "no result for check"}
+		case r.Error != nil:
+			code := http.StatusInternalServerError
+			if r.Error.InputError != nil {
+				code = http.StatusBadRequest
+			}
+			results[i].Err = &httpError{code: code, msg: r.Error.Message}
+		default:
+			results[i].Allowed = r.Allowed
+		}
+	}
+	return results, nil
+}
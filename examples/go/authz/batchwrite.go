@@ -0,0 +1,162 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+// MaxTuplesPerWrite is the largest number of tuple keys OpenFGA accepts in a
+// single transactional Write.
+const MaxTuplesPerWrite = 100
+
+// BatchWriteOptions configures WriteTuplesBatched.
+type BatchWriteOptions struct {
+	// NonTransactional keeps submitting the remaining chunks after one
+	// fails. The returned *BatchWriteError lists which chunks landed.
+	NonTransactional bool
+	// ModelID validates every chunk against this authorization model; see
+	// WriteOptions.ModelID.
+	ModelID string
+}
+
+// ChunkError records the failure of one chunk of a batched write.
+type ChunkError struct {
+	Index int
+	Err   error
+}
+
+// BatchWriteError reports the outcome of a batched write in which at least
+// one chunk failed. Chunk indexes are zero-based in submission order.
+type BatchWriteError struct {
+	Succeeded []int
+	Failed    []ChunkError
+}
+
+func (e *BatchWriteError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "batched write: %d of %d chunks failed", len(e.Failed), len(e.Failed)+len(e.Succeeded))
+	for _, f := range e.Failed {
+		fmt.Fprintf(&b, "; chunk %d: %v", f.Index, f.Err)
+	}
+	return b.String()
+}
+
+// Unwrap exposes the per-chunk errors to errors.Is and errors.As.
+func (e *BatchWriteError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// WriteReceipt records what a batched write stored, for audit logs.
+type WriteReceipt struct {
+	// Tuples are the tuples submitted, after repeats were dropped.
+	Tuples []client.ClientTupleKey
+	// Chunks lists the chunks that were written, in submission order.
+	Chunks []ChunkReceipt
+	// DryRun is set when the client is in dry-run mode: nothing was
+	// written and no chunk has a WrittenAt.
+	DryRun bool
+}
+
+// Written returns the number of tuples in the written chunks.
+func (r WriteReceipt) Written() int {
+	n := 0
+	for _, ch := range r.Chunks {
+		n += len(ch.Tuples)
+	}
+	return n
+}
+
+// ChunkReceipt records one written chunk of a batched write.
+type ChunkReceipt struct {
+	Index  int
+	Tuples []client.ClientTupleKey
+	// WrittenAt is the server's timestamp of the chunk's transaction, read
+	// back from a stored tuple since the Write response carries none. It is
+	// zero if the read-back failed, which is logged but does not fail the
+	// write.
+	WrittenAt time.Time
+}
+
+// WriteTuplesBatched writes tuples in sequential chunks of at most
+// MaxTuplesPerWrite, after dropping repeats as Write does. Each chunk is
+// atomic on its own, but the batch as a whole is not: by default the first
+// failing chunk stops the batch and earlier chunks stay written.
+//
+// The receipt lists the chunks that were written, also when an error is
+// returned. Reading back each chunk's timestamp costs one Read per chunk;
+// Grant writes the same way without a receipt and skips those reads.
+func (c *Client) WriteTuplesBatched(ctx context.Context, tuples []client.ClientTupleKey, opts BatchWriteOptions) (WriteReceipt, error) {
+	return c.writeBatched(ctx, tuples, opts, true)
+}
+
+// writeBatched implements WriteTuplesBatched, reading back each chunk's
+// WrittenAt only when timestamps is set.
+func (c *Client) writeBatched(ctx context.Context, tuples []client.ClientTupleKey, opts BatchWriteOptions, timestamps bool) (WriteReceipt, error) {
+	receipt := WriteReceipt{DryRun: c.dryRun}
+	// Validate before deduplicating, so problem indexes match the input.
+	if err := c.validateWrites(ctx, tuples, opts.ModelID); err != nil {
+		return receipt, err
+	}
+	req, err := dedupeWrite(client.ClientWriteRequest{Writes: tuples})
+	if err != nil {
+		return receipt, c.redactErr(err, writeValues(req)...)
+	}
+	receipt.Tuples = req.Writes
+	var batchErr BatchWriteError
+	for i, chunk := range chunkTuples(receipt.Tuples, MaxTuplesPerWrite) {
+		err := c.write(ctx, client.ClientWriteRequest{Writes: chunk}, opts.ModelID)
+		if err != nil {
+			batchErr.Failed = append(batchErr.Failed, ChunkError{Index: i, Err: err})
+			if !opts.NonTransactional || ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		batchErr.Succeeded = append(batchErr.Succeeded, i)
+		ch := ChunkReceipt{Index: i, Tuples: chunk}
+		if timestamps {
+			ch.WrittenAt = c.writtenAt(ctx, chunk[0])
+		}
+		receipt.Chunks = append(receipt.Chunks, ch)
+	}
+	if len(batchErr.Failed) > 0 {
+		return receipt, &batchErr
+	}
+	return receipt, nil
+}
+
+// writtenAt returns the server timestamp of the stored tuple tk, or the
+// zero time in dry-run mode or if it cannot be read.
+func (c *Client) writtenAt(ctx context.Context, tk client.ClientTupleKey) time.Time {
+	if c.dryRun {
+		return time.Time{}
+	}
+	tuples, err := c.ReadTuples(ctx, client.ClientReadRequest{User: &tk.User, Relation: &tk.Relation, Object: &tk.Object}, 1)
+	if err != nil || len(tuples) == 0 {
+		c.logger().Warn("could not read back write timestamp", "operation", "WriteTuplesBatched",
+			"object", c.redactValue(tk.Object), "relation", tk.Relation, "error", err)
+		return time.Time{}
+	}
+	return tuples[0].Timestamp
+}
+
+// chunkTuples splits s into consecutive slices of at most size elements.
+func chunkTuples[T any](s []T, size int) [][]T {
+	var chunks [][]T
+	for len(s) > size {
+		chunks = append(chunks, s[:size:size])
+		s = s[size:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
@@ -0,0 +1,66 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// writeReadStub accepts every Write and answers every Read with a tuple
+// stamped at stubWrittenAt, counting both.
+type writeReadStub struct {
+	FGA
+	writes, reads int
+}
+
+var stubWrittenAt = time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+
+func (s *writeReadStub) Write(ctx context.Context, body client.ClientWriteRequest, opts client.ClientWriteOptions) (*client.ClientWriteResponse, error) {
+	s.writes++
+	return &client.ClientWriteResponse{}, nil
+}
+
+func (s *writeReadStub) Read(ctx context.Context, body client.ClientReadRequest, opts client.ClientReadOptions) (*client.ClientReadResponse, error) {
+	s.reads++
+	key := openfga.TupleKey{User: *body.User, Relation: *body.Relation, Object: *body.Object}
+	return &client.ClientReadResponse{Tuples: []openfga.Tuple{{Key: key, Timestamp: stubWrittenAt}}}, nil
+}
+
+func TestBatchedWriteReadBack(t *testing.T) {
+	tuples := make([]client.ClientTupleKey, 2*MaxTuplesPerWrite+1)
+	for i := range tuples {
+		tuples[i] = client.ClientTupleKey{User: fmt.Sprintf("user:%d", i), Relation: "viewer", Object: "document:1"}
+	}
+
+	t.Run("Grant", func(t *testing.T) {
+		stub := &writeReadStub{}
+		c := NewWithFGA(stub, Config{StoreID: "01HVMMBCMGZNT3SED4Z17ECXCA"})
+		if err := c.Grant(context.Background(), tuples...); err != nil {
+			t.Fatal(err)
+		}
+		if stub.writes != 3 || stub.reads != 0 {
+			t.Errorf("Grant made %d writes and %d reads, want 3 and 0", stub.writes, stub.reads)
+		}
+	})
+
+	t.Run("WriteTuplesBatched", func(t *testing.T) {
+		stub := &writeReadStub{}
+		c := NewWithFGA(stub, Config{StoreID: "01HVMMBCMGZNT3SED4Z17ECXCA"})
+		receipt, err := c.WriteTuplesBatched(context.Background(), tuples, BatchWriteOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stub.writes != 3 || stub.reads != 3 {
+			t.Errorf("WriteTuplesBatched made %d writes and %d reads, want 3 and 3", stub.writes, stub.reads)
+		}
+		for _, ch := range receipt.Chunks {
+			if !ch.WrittenAt.Equal(stubWrittenAt) {
+				t.Errorf("chunk %d written at %v, want %v", ch.Index, ch.WrittenAt, stubWrittenAt)
+			}
+		}
+	})
+}
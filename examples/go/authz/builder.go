@@ -0,0 +1,177 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// Rewrite is a relation definition for use with ModelBuilder.Relation.
+// Build rewrites with DirectTo, Computed, From, Or, And and ButNot.
+type Rewrite struct {
+	userset openfga.Userset
+	direct  []openfga.RelationReference
+	err     error
+}
+
+// DirectTo allows tuples assigning the listed user types directly, written
+// as in the DSL: "user", "user:*", "group#member" or "user with cond".
+func DirectTo(types ...string) Rewrite {
+	if len(types) == 0 {
+		return Rewrite{err: fmt.Errorf("DirectTo needs at least one type")}
+	}
+	refs := make([]openfga.RelationReference, 0, len(types))
+	for _, t := range types {
+		ref, err := parseRelationReference(strings.TrimSpace(t))
+		if err != nil {
+			return Rewrite{err: err}
+		}
+		refs = append(refs, ref)
+	}
+	return Rewrite{userset: openfga.Userset{This: &map[string]interface{}{}}, direct: refs}
+}
+
+// Computed grants the relation to everyone holding relation on the same
+// object, e.g. Computed("owner") for "define editor: owner".
+func Computed(relation string) Rewrite {
+	return Rewrite{userset: computed(relation)}
+}
+
+// From grants the relation through a related object, e.g.
+// From("admin", "parent") for "define admin: admin from parent".
+func From(relation, tupleset string) Rewrite {
+	return Rewrite{userset: tupleToUserset(relation, tupleset)}
+}
+
+// Or is the union of rewrites: "a or b".
+func Or(rewrites ...Rewrite) Rewrite {
+	return combine(rewrites, func(children []openfga.Userset) openfga.Userset {
+		return openfga.Userset{Union: &openfga.Usersets{Child: children}}
+	})
+}
+
+// And is the intersection of rewrites: "a and b".
+func And(rewrites ...Rewrite) Rewrite {
+	return combine(rewrites, func(children []openfga.Userset) openfga.Userset {
+		return openfga.Userset{Intersection: &openfga.Usersets{Child: children}}
+	})
+}
+
+// ButNot excludes subtract from base: "base but not subtract".
+func ButNot(base, subtract Rewrite) Rewrite {
+	return combine([]Rewrite{base, subtract}, func(children []openfga.Userset) openfga.Userset {
+		return openfga.Userset{Difference: &openfga.Difference{Base: children[0], Subtract: children[1]}}
+	})
+}
+
+func combine(rewrites []Rewrite, build func([]openfga.Userset) openfga.Userset) Rewrite {
+	if len(rewrites) == 0 {
+		return Rewrite{err: fmt.Errorf("operator needs at least one operand")}
+	}
+	if len(rewrites) == 1 {
+		return rewrites[0]
+	}
+	var out Rewrite
+	children := make([]openfga.Userset, 0, len(rewrites))
+	for _, rw := range rewrites {
+		if rw.err != nil {
+			return rw
+		}
+		if rw.direct != nil {
+			if out.direct != nil {
+				return Rewrite{err: fmt.Errorf("more than one direct assignment")}
+			}
+			out.direct = rw.direct
+		}
+		children = append(children, rw.userset)
+	}
+	out.userset = build(children)
+	return out
+}
+
+// ModelBuilder assembles type definitions programmatically as an
+// alternative to writing DSL:
+//
+//	typeDefs, err := NewModelBuilder().
+//		Type("user").
+//		Type("organization").
+//		Relation("admin", DirectTo("user")).
+//		Relation("member", Or(DirectTo("user"), Computed("admin"))).
+//		Build()
+type ModelBuilder struct {
+	types []openfga.TypeDefinition
+	seen  map[string]bool
+	err   error
+}
+
+// NewModelBuilder returns an empty builder.
+func NewModelBuilder() *ModelBuilder {
+	return &ModelBuilder{seen: map[string]bool{}}
+}
+
+// Type starts a new type definition. Subsequent Relation calls attach to it.
+func (b *ModelBuilder) Type(name string) *ModelBuilder {
+	if b.err != nil {
+		return b
+	}
+	if !isIdentifier(name) {
+		b.err = fmt.Errorf("invalid type name %q", name)
+		return b
+	}
+	if b.seen[name] {
+		b.err = fmt.Errorf("duplicate type %q", name)
+		return b
+	}
+	b.seen[name] = true
+	b.types = append(b.types, openfga.TypeDefinition{Type: name})
+	return b
+}
+
+// Relation defines a relation on the most recently added type.
+func (b *ModelBuilder) Relation(name string, rw Rewrite) *ModelBuilder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.types) == 0 {
+		b.err = fmt.Errorf("relation %q defined before any type", name)
+		return b
+	}
+	td := &b.types[len(b.types)-1]
+	switch {
+	case !isIdentifier(name):
+		b.err = fmt.Errorf("type %q: invalid relation name %q", td.Type, name)
+		return b
+	case rw.err != nil:
+		b.err = fmt.Errorf("type %q relation %q: %w", td.Type, name, rw.err)
+		return b
+	}
+	if td.Relations == nil {
+		td.Relations = &map[string]openfga.Userset{}
+		td.Metadata = &openfga.Metadata{Relations: &map[string]openfga.RelationMetadata{}}
+	}
+	if _, dup := (*td.Relations)[name]; dup {
+		b.err = fmt.Errorf("duplicate relation %q on type %q", name, td.Type)
+		return b
+	}
+	direct := rw.direct
+	if direct == nil {
+		direct = []openfga.RelationReference{}
+	}
+	(*td.Relations)[name] = rw.userset
+	(*td.Metadata.Relations)[name] = openfga.RelationMetadata{DirectlyRelatedUserTypes: &direct}
+	return b
+}
+
+// Build returns the type definitions, or the first error encountered while
+// building them. Every type and relation referenced by a rewrite must be
+// defined; the error names the first dangling reference.
+func (b *ModelBuilder) Build() ([]openfga.TypeDefinition, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := validateTypeDefs(b.types); err != nil {
+		return nil, err
+	}
+	return b.types, nil
+}
@@ -0,0 +1,167 @@
+package authz
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheConfig enables caching of Check results. Both fields must be set;
+// the zero value disables the cache.
+type CacheConfig struct {
+	// TTL bounds how long a result is served from the cache. Writes through
+	// the same Client evict results for the objects they touch, but a write
+	// can also change results for other objects (e.g. adding a user to a
+	// group), and writes by other processes are not seen at all, so TTL is
+	// the upper bound on staleness.
+	TTL time.Duration
+	// MaxEntries is the number of results kept; the least recently used
+	// result is evicted first.
+	MaxEntries int
+}
+
+// CacheStats reports Check cache effectiveness.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+type checkKey struct {
+	store, model, user, relation, object string
+	// params hashes contextual tuples and context; empty when neither is set.
+	params string
+}
+
+type cacheEntry struct {
+	key     checkKey
+	allowed bool
+	expires time.Time
+}
+
+// checkCache is an LRU cache of Check results with per-entry expiry.
+type checkCache struct {
+	ttl time.Duration
+	max int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[checkKey]*list.Element
+
+	hits, misses atomic.Uint64
+}
+
+func newCheckCache(cfg CacheConfig) *checkCache {
+	if cfg.TTL <= 0 || cfg.MaxEntries <= 0 {
+		return nil
+	}
+	return &checkCache{
+		ttl:   cfg.TTL,
+		max:   cfg.MaxEntries,
+		ll:    list.New(),
+		items: map[checkKey]*list.Element{},
+	}
+}
+
+func (cc *checkCache) get(k checkKey) (allowed, ok bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	el, ok := cc.items[k]
+	if ok && time.Now().After(el.Value.(*cacheEntry).expires) {
+		cc.remove(el)
+		ok = false
+	}
+	if !ok {
+		cc.misses.Add(1)
+		return false, false
+	}
+	cc.hits.Add(1)
+	cc.ll.MoveToFront(el)
+	return el.Value.(*cacheEntry).allowed, true
+}
+
+func (cc *checkCache) put(k checkKey, allowed bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	expires := time.Now().Add(cc.ttl)
+	if el, ok := cc.items[k]; ok {
+		e := el.Value.(*cacheEntry)
+		e.allowed, e.expires = allowed, expires
+		cc.ll.MoveToFront(el)
+		return
+	}
+	cc.items[k] = cc.ll.PushFront(&cacheEntry{key: k, allowed: allowed, expires: expires})
+	for cc.ll.Len() > cc.max {
+		cc.remove(cc.ll.Back())
+	}
+}
+
+// invalidate evicts every result for the given objects in store.
+func (cc *checkCache) invalidate(store string, objects map[string]bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	for el := cc.ll.Front(); el != nil; {
+		next := el.Next()
+		if k := el.Value.(*cacheEntry).key; k.store == store && objects[k.object] {
+			cc.remove(el)
+		}
+		el = next
+	}
+}
+
+// retainModel evicts every result for store computed against a model other
+// than model.
+func (cc *checkCache) retainModel(store, model string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	for el := cc.ll.Front(); el != nil; {
+		next := el.Next()
+		if k := el.Value.(*cacheEntry).key; k.store == store && k.model != model {
+			cc.remove(el)
+		}
+		el = next
+	}
+}
+
+// clear evicts every result.
+func (cc *checkCache) clear() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.ll.Init()
+	cc.items = map[checkKey]*list.Element{}
+}
+
+func (cc *checkCache) remove(el *list.Element) {
+	cc.ll.Remove(el)
+	delete(cc.items, el.Value.(*cacheEntry).key)
+}
+
+// CacheStats returns the Check cache's hit and miss counts. Both are zero
+// when Config.CheckCache is unset.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return CacheStats{Hits: c.cache.hits.Load(), Misses: c.cache.misses.Load()}
+}
+
+// paramsHash returns a digest of the request-scoped inputs to a query, so
+// results with different contextual tuples or context are cached apart. It
+// reports false if the parameters cannot be hashed and must not be cached.
+func (p queryParams) paramsHash() (string, bool) {
+	if len(p.contextualTuples) == 0 && p.context == nil {
+		return "", true
+	}
+	b, err := json.Marshal(struct {
+		Tuples  interface{} `json:"t"`
+		Context interface{} `json:"c"`
+	}{p.contextualTuples, p.context})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), true
+}
@@ -0,0 +1,141 @@
+package authz
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// modelsEqual reports whether a and b describe the same authorization model,
+// ignoring the order of type definitions, relations, type restrictions and
+// union or intersection operands, as well as source metadata.
+func modelsEqual(a, b client.ClientWriteAuthorizationModelRequest) bool {
+	ca, errA := canonicalModel(a)
+	cb, errB := canonicalModel(b)
+	return errA == nil && errB == nil && bytes.Equal(ca, cb)
+}
+
+// canonicalModel returns a byte encoding of m that is identical for
+// semantically equal models.
+func canonicalModel(m client.ClientWriteAuthorizationModelRequest) ([]byte, error) {
+	tds := make([]openfga.TypeDefinition, len(m.TypeDefinitions))
+	for i, td := range m.TypeDefinitions {
+		tds[i] = canonicalTypeDef(td)
+	}
+	sort.Slice(tds, func(i, j int) bool { return tds[i].Type < tds[j].Type })
+
+	var conds map[string]openfga.Condition
+	if m.Conditions != nil && len(*m.Conditions) > 0 {
+		conds = make(map[string]openfga.Condition, len(*m.Conditions))
+		for name, cond := range *m.Conditions {
+			cond.Metadata = nil
+			conds[name] = cond
+		}
+	}
+	return json.Marshal(struct {
+		SchemaVersion string
+		Types         []openfga.TypeDefinition
+		Conditions    map[string]openfga.Condition
+	}{m.SchemaVersion, tds, conds})
+}
+
+func canonicalTypeDef(td openfga.TypeDefinition) openfga.TypeDefinition {
+	out := openfga.TypeDefinition{Type: td.Type}
+	if td.Relations != nil && len(*td.Relations) > 0 {
+		rels := make(map[string]openfga.Userset, len(*td.Relations))
+		for name, us := range *td.Relations {
+			rels[name] = canonicalUserset(us)
+		}
+		out.Relations = &rels
+	}
+	if td.Metadata != nil && td.Metadata.Relations != nil {
+		md := make(map[string]openfga.RelationMetadata)
+		for name, rm := range *td.Metadata.Relations {
+			if rm.DirectlyRelatedUserTypes == nil || len(*rm.DirectlyRelatedUserTypes) == 0 {
+				continue
+			}
+			refs := append([]openfga.RelationReference(nil), *rm.DirectlyRelatedUserTypes...)
+			sort.Slice(refs, func(i, j int) bool { return refKey(refs[i]) < refKey(refs[j]) })
+			md[name] = openfga.RelationMetadata{DirectlyRelatedUserTypes: &refs}
+		}
+		if len(md) > 0 {
+			out.Metadata = &openfga.Metadata{Relations: &md}
+		}
+	}
+	return out
+}
+
+func canonicalUserset(us openfga.Userset) openfga.Userset {
+	switch {
+	case us.This != nil:
+		return openfga.Userset{This: &map[string]interface{}{}}
+	case us.ComputedUserset != nil:
+		return openfga.Userset{ComputedUserset: canonicalObjectRelation(*us.ComputedUserset)}
+	case us.TupleToUserset != nil:
+		return openfga.Userset{TupleToUserset: &openfga.TupleToUserset{
+			Tupleset:        *canonicalObjectRelation(us.TupleToUserset.Tupleset),
+			ComputedUserset: *canonicalObjectRelation(us.TupleToUserset.ComputedUserset),
+		}}
+	case us.Union != nil:
+		return openfga.Userset{Union: canonicalChildren(us.Union.Child)}
+	case us.Intersection != nil:
+		return openfga.Userset{Intersection: canonicalChildren(us.Intersection.Child)}
+	case us.Difference != nil:
+		return openfga.Userset{Difference: &openfga.Difference{
+			Base:     canonicalUserset(us.Difference.Base),
+			Subtract: canonicalUserset(us.Difference.Subtract),
+		}}
+	}
+	return us
+}
+
+// canonicalChildren canonicalises and sorts the operands of a commutative
+// rewrite.
+func canonicalChildren(children []openfga.Userset) *openfga.Usersets {
+	type keyed struct {
+		key string
+		us  openfga.Userset
+	}
+	ks := make([]keyed, len(children))
+	for i, child := range children {
+		c := canonicalUserset(child)
+		b, _ := json.Marshal(c)
+		ks[i] = keyed{string(b), c}
+	}
+	sort.Slice(ks, func(i, j int) bool { return ks[i].key < ks[j].key })
+	out := make([]openfga.Userset, len(ks))
+	for i, k := range ks {
+		out[i] = k.us
+	}
+	return &openfga.Usersets{Child: out}
+}
+
+func canonicalObjectRelation(or openfga.ObjectRelation) *openfga.ObjectRelation {
+	out := openfga.ObjectRelation{}
+	if or.Object != nil && *or.Object != "" {
+		out.Object = or.Object
+	}
+	if or.Relation != nil && *or.Relation != "" {
+		out.Relation = or.Relation
+	}
+	return &out
+}
+
+// refKey renders a type restriction in DSL form, e.g. "group#member" or
+// "user:* with cond".
+func refKey(ref openfga.RelationReference) string {
+	s := ref.Type
+	switch {
+	case ref.Wildcard != nil:
+		s += ":*"
+	case ref.Relation != nil && *ref.Relation != "":
+		s += "#" + *ref.Relation
+	}
+	if ref.Condition != nil && *ref.Condition != "" {
+		s += " with " + *ref.Condition
+	}
+	return s
+}
@@ -0,0 +1,203 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// WatchOptions configures WatchChanges.
+type WatchOptions struct {
+	// Type restricts the feed to one object type. Empty watches every type.
+	Type string
+	// StartToken resumes from a previously checkpointed token. Empty starts
+	// from the beginning of the store's history.
+	StartToken string
+	// PollInterval is the initial wait after a poll returns no changes.
+	// Defaults to one second.
+	PollInterval time.Duration
+	// MaxInterval caps the backoff between empty polls. Defaults to 30s.
+	MaxInterval time.Duration
+	// OnCheckpoint, if set, is called with the latest continuation token
+	// after each page has been delivered. Persist it to resume after a
+	// restart.
+	OnCheckpoint func(token string)
+}
+
+// WatchChanges streams every tuple write and delete to out, polling the
+// ReadChanges endpoint until ctx is cancelled. When a poll returns nothing
+// new it backs off exponentially up to MaxInterval, and resets to
+// PollInterval as soon as changes arrive. It returns ctx.Err() on
+// cancellation or the first failed poll.
+func (c *Client) WatchChanges(ctx context.Context, opts WatchOptions, out chan<- openfga.TupleChange) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = 30 * time.Second
+	}
+
+	token := opts.StartToken
+	wait := interval
+	for {
+		resp, err := c.readChanges(ctx, opts.Type, token)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		for _, change := range resp.Changes {
+			select {
+			case out <- change:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if resp.ContinuationToken != nil && *resp.ContinuationToken != "" {
+			token = *resp.ContinuationToken
+		}
+		if opts.OnCheckpoint != nil && len(resp.Changes) > 0 {
+			opts.OnCheckpoint(token)
+		}
+
+		if len(resp.Changes) > 0 {
+			wait = interval
+			continue
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wait *= 2
+		if wait > maxInterval {
+			wait = maxInterval
+		}
+	}
+}
+
+// readChanges reads one page of the change log of objType, or of every
+// type if it is empty, after token.
+func (c *Client) readChanges(ctx context.Context, objType, token string) (*client.ClientReadChangesResponse, error) {
+	var resp *client.ClientReadChangesResponse
+	err := c.call(ctx, "ReadChanges", func(ctx context.Context) (err error) {
+		resp, err = c.fga.ReadChanges(ctx, client.ClientReadChangesRequest{Type: objType}, client.ClientReadChangesOptions{
+			ContinuationToken: optional(token),
+			StoreId:           &c.StoreID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read changes: %w", err)
+	}
+	return resp, nil
+}
+
+// ReplayChanges applies the change log of src's store after fromToken, or
+// its whole history if fromToken is empty, to dst's store in order, e.g. to
+// migrate a store between deployments. Writes keep their conditions. It
+// returns the token to resume from: the end of the log, or on error the
+// last page that was fully applied.
+//
+// The destination's state is tracked per tuple, looked up with one Read the
+// first time a tuple appears. A delete of a tuple dst does not hold, e.g.
+// one written before the replay window, is skipped, as is a write of a
+// tuple dst already holds, so replaying a page twice after resuming is
+// harmless. Consecutive changes are sent in batches of up to
+// MaxTuplesPerWrite, each transactional.
+func ReplayChanges(ctx context.Context, src, dst *Client, fromToken string) (string, error) {
+	r := replay{dst: dst, present: map[string]bool{}}
+	token := fromToken
+	for {
+		resp, err := src.readChanges(ctx, "", token)
+		if err != nil {
+			return token, fmt.Errorf("replay changes: %w", err)
+		}
+		if len(resp.Changes) == 0 {
+			return token, nil
+		}
+		for _, change := range resp.Changes {
+			if err := r.apply(ctx, change); err != nil {
+				return token, fmt.Errorf("replay changes: %w", err)
+			}
+		}
+		if err := r.flush(ctx); err != nil {
+			return token, fmt.Errorf("replay changes: %w", err)
+		}
+		r.seen += len(resp.Changes)
+		if next := deref(resp.ContinuationToken); next != "" {
+			token = next
+		}
+		dst.logger().Debug("replayed changes", "operation", "ReplayChanges",
+			"changes", r.seen, "skipped", r.skipped)
+	}
+}
+
+// replay batches the effective changes of ReplayChanges.
+type replay struct {
+	dst *Client
+	// present records whether dst holds each tuple seen so far, as of the
+	// changes applied or pending.
+	present map[string]bool
+	pending client.ClientWriteRequest
+	keys    map[string]bool
+
+	seen, skipped int
+}
+
+func (r *replay) apply(ctx context.Context, change openfga.TupleChange) error {
+	tk := change.TupleKey
+	k := tk.Object + "#" + tk.Relation + "@" + tk.User
+	held, known := r.present[k]
+	if !known {
+		ok, err := r.dst.HasTuple(ctx, client.ClientTupleKeyWithoutCondition{User: tk.User, Relation: tk.Relation, Object: tk.Object})
+		if err != nil {
+			return err
+		}
+		held = ok
+	}
+	write := change.Operation == openfga.TUPLEOPERATION_WRITE
+	if write == held {
+		r.present[k] = held
+		r.skipped++
+		return nil
+	}
+	// A request may not name a tuple twice, nor exceed the write limit.
+	if r.keys[k] || len(r.pending.Writes)+len(r.pending.Deletes) >= MaxTuplesPerWrite {
+		if err := r.flush(ctx); err != nil {
+			return err
+		}
+	}
+	if write {
+		r.pending.Writes = append(r.pending.Writes, client.ClientTupleKey{
+			User: tk.User, Relation: tk.Relation, Object: tk.Object, Condition: tk.Condition,
+		})
+	} else {
+		r.pending.Deletes = append(r.pending.Deletes, client.ClientTupleKeyWithoutCondition{
+			User: tk.User, Relation: tk.Relation, Object: tk.Object,
+		})
+	}
+	if r.keys == nil {
+		r.keys = map[string]bool{}
+	}
+	r.keys[k] = true
+	r.present[k] = write
+	return nil
+}
+
+func (r *replay) flush(ctx context.Context) error {
+	if len(r.pending.Writes) == 0 && len(r.pending.Deletes) == 0 {
+		return nil
+	}
+	err := r.dst.write(ctx, r.pending, "")
+	r.pending = client.ClientWriteRequest{}
+	r.keys = nil
+	return err
+}
@@ -0,0 +1,153 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// Check reports whether user has relation on object. The user may be a
+// concrete object, a wildcard or a userset; see ParseUser. An empty user
+// defaults to the one attached to ctx by WithUser.
+func (c *Client) Check(ctx context.Context, user, relation, object string, opts ...QueryOption) (_ bool, err error) {
+	user = userOrContext(ctx, user)
+	ctx, span := c.startSpan(ctx, "Check", relationAttr(relation), objectTypeAttr(object))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := ParseUser(user); err != nil {
+		return false, fmt.Errorf("check: %w", c.redactErr(err, user))
+	}
+
+	p := newQueryParams(opts)
+	modelID, err := c.queryModel(ctx, p)
+	if err != nil {
+		return false, err
+	}
+	setSpanModel(span, modelID)
+
+	var key checkKey
+	cacheable := false
+	if c.cache != nil && modelID != nil {
+		key = checkKey{store: c.StoreID, model: *modelID, user: user, relation: relation, object: object}
+		key.params, cacheable = p.paramsHash()
+	}
+	if cacheable && !p.strong() && !p.refresh {
+		if allowed, ok := c.cache.get(key); ok {
+			return allowed, nil
+		}
+	}
+
+	var resp *client.ClientCheckResponse
+	err = c.call(ctx, "Check", func(ctx context.Context) (err error) {
+		resp, err = c.fga.Check(ctx, client.ClientCheckRequest{
+			User:             user,
+			Relation:         relation,
+			Object:           object,
+			Context:          p.contextPtr(),
+			ContextualTuples: p.contextualTuples,
+		}, client.ClientCheckOptions{
+			AuthorizationModelId: modelID,
+			StoreId:              &c.StoreID,
+			Consistency:          p.consistency,
+		})
+		return c.redactErr(err, queryValues(p.contextualTuples, user, object)...)
+	})
+	if err != nil {
+		return false, fmt.Errorf("check: %w", err)
+	}
+	if cacheable {
+		c.cache.put(key, resp.GetAllowed())
+	}
+	return resp.GetAllowed(), nil
+}
+
+// SimulateCheck answers a what-if question: would base be allowed if the
+// extra tuples were stored, with checkCtx supplying condition parameters?
+// Nothing is written; the tuples are sent as contextual tuples alongside
+// the context, and conditions on them or on stored tuples are evaluated
+// against checkCtx. It is Check with WithContextualTuples and WithContext,
+// and opts may add to both.
+func (c *Client) SimulateCheck(ctx context.Context, base openfga.CheckRequestTupleKey, extra []client.ClientContextualTupleKey, checkCtx map[string]interface{}, opts ...QueryOption) (bool, error) {
+	sim := make([]QueryOption, 0, len(opts)+2)
+	if len(extra) > 0 {
+		sim = append(sim, WithContextualTuples(extra...))
+	}
+	if checkCtx != nil {
+		sim = append(sim, WithContext(checkCtx))
+	}
+	return c.Check(ctx, base.User, base.Relation, base.Object, append(sim, opts...)...)
+}
+
+// ListObjects returns the objects of objType on which user has relation.
+// The API does not paginate ListObjects: the server returns every object in
+// one response, up to its result limit (see Config.ListObjectsLimit). A
+// result that reaches the limit is assumed truncated and is fetched again
+// from the streamed endpoint, which has no limit. If the FGA implementation
+// does not implement ObjectStreamer, or the stream fails, the partial
+// result is returned with an error matching ErrResultTruncated. To hand
+// results on as they are found, e.g. to a UI, use StreamListObjects. An
+// empty user defaults to the one attached to ctx by WithUser.
+//
+// Objects reached only through conditional tuples are listed only if the
+// condition holds for the values supplied with WithContext, e.g. documents
+// a user can view right now:
+//
+//	docs, err := fga.ListObjects(ctx, "user:anne", "viewer", "document",
+//		authz.WithContext(map[string]interface{}{"current_time": time.Now().UTC().Format(time.RFC3339)}))
+func (c *Client) ListObjects(ctx context.Context, user, relation, objType string, opts ...QueryOption) (_ []string, err error) {
+	user = userOrContext(ctx, user)
+	ctx, span := c.startSpan(ctx, "ListObjects", relationAttr(relation), objectTypeAttr(objType))
+	defer func() { endSpan(span, err) }()
+
+	p := newQueryParams(opts)
+	modelID, err := c.queryModel(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	setSpanModel(span, modelID)
+	body := client.ClientListObjectsRequest{
+		User:             user,
+		Relation:         relation,
+		Type:             objType,
+		Context:          p.contextPtr(),
+		ContextualTuples: p.contextualTuples,
+	}
+	listOpts := client.ClientListObjectsOptions{
+		AuthorizationModelId: modelID,
+		StoreId:              &c.StoreID,
+		Consistency:          p.consistency,
+	}
+	var resp *client.ClientListObjectsResponse
+	err = c.call(ctx, "ListObjects", func(ctx context.Context) (err error) {
+		resp, err = c.fga.ListObjects(ctx, body, listOpts)
+		return c.redactErr(err, queryValues(p.contextualTuples, user)...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list objects: %w", err)
+	}
+	if c.listLimit <= 0 || len(resp.Objects) < c.listLimit {
+		return resp.Objects, nil
+	}
+
+	streamer, ok := c.fga.(ObjectStreamer)
+	if !ok {
+		return resp.Objects, fmt.Errorf("list objects: %w (%d objects)", ErrResultTruncated, len(resp.Objects))
+	}
+	var objects []string
+	err = c.call(ctx, "StreamedListObjects", func(ctx context.Context) error {
+		objects = objects[:0]
+		err := streamer.StreamedListObjects(ctx, body, listOpts, func(object string) error {
+			objects = append(objects, object)
+			return nil
+		})
+		return c.redactErr(err, queryValues(p.contextualTuples, user)...)
+	})
+	if err != nil {
+		return resp.Objects, fmt.Errorf("list objects: %w (%d objects): streamed fallback: %w", ErrResultTruncated, len(resp.Objects), err)
+	}
+	c.logger().Info("list objects hit the server's result limit; used the streamed endpoint",
+		"operation", "ListObjects", "limit", c.listLimit, "objects", len(objects))
+	return objects, nil
+}
@@ -0,0 +1,291 @@
+package authz_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	"github.com/bogdanticu88/openfga-examples/authz/fake"
+	"github.com/bogdanticu88/openfga-examples/authz/testutil"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// benchCaches are the cache settings the benchmarks run under.
+var benchCaches = []struct {
+	name  string
+	cache authz.CacheConfig
+}{
+	{"cache off", authz.CacheConfig{}},
+	{"cache on", authz.CacheConfig{TTL: time.Minute, MaxEntries: 10000}},
+}
+
+// benchClient returns a client over the fake holding the sample model, with
+// user:anne a member of organization:acme, which may view project:0 through
+// project:99.
+func benchClient(b *testing.B, cache authz.CacheConfig) *authz.Client {
+	b.Helper()
+	ctx := context.Background()
+	c := authz.NewWithFGA(fake.New(), authz.Config{CheckCache: cache})
+	b.Cleanup(func() { c.Close() })
+	if _, err := c.CreateStore(ctx, "bench"); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := c.WriteModelFromFile(ctx, "../model.fga"); err != nil {
+		b.Fatal(err)
+	}
+	tuples := []client.ClientTupleKey{{User: "user:anne", Relation: "member", Object: "organization:acme"}}
+	for i := 0; i < 100; i++ {
+		tuples = append(tuples, client.ClientTupleKey{User: "organization:acme#member", Relation: "viewer", Object: fmt.Sprintf("project:%d", i)})
+	}
+	if err := c.Grant(ctx, tuples...); err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+func BenchmarkCheck(b *testing.B) {
+	for _, bc := range benchCaches {
+		b.Run(bc.name, func(b *testing.B) {
+			c := benchClient(b, bc.cache)
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				allowed, err := c.Check(ctx, "user:anne", "viewer", fmt.Sprintf("project:%d", i%100))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if !allowed {
+					b.Fatal("Check denied, want allowed")
+				}
+			}
+		})
+	}
+}
+
+func TestCheckButNot(t *testing.T) {
+	fga := testutil.LoadFixture(t, `model
+  schema 1.1
+
+type user
+
+type document
+  relations
+    define banned: [user]
+    define viewer: [user] but not banned
+`, []client.ClientTupleKey{
+		{User: "user:anne", Relation: "viewer", Object: "document:plan"},
+		{User: "user:bob", Relation: "viewer", Object: "document:plan"},
+		{User: "user:bob", Relation: "banned", Object: "document:plan"},
+	})
+	tests := []struct {
+		user string
+		want bool
+	}{
+		{"user:anne", true},
+		{"user:bob", false},
+		{"user:carol", false},
+	}
+	for _, tt := range tests {
+		allowed, err := fga.Check(context.Background(), tt.user, "viewer", "document:plan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != tt.want {
+			t.Errorf("Check(%s viewer document:plan) = %v, want %v", tt.user, allowed, tt.want)
+		}
+	}
+}
+
+func TestCheckIntersection(t *testing.T) {
+	fga := testutil.LoadFixture(t, `model
+  schema 1.1
+
+type user
+
+type document
+  relations
+    define editor: [user]
+    define mfa_verified: [user]
+    define can_publish: editor and mfa_verified
+`, []client.ClientTupleKey{
+		{User: "user:anne", Relation: "editor", Object: "document:plan"},
+		{User: "user:anne", Relation: "mfa_verified", Object: "document:plan"},
+		{User: "user:bob", Relation: "editor", Object: "document:plan"},
+		{User: "user:carol", Relation: "mfa_verified", Object: "document:plan"},
+	})
+	tests := []struct {
+		user string
+		want bool
+	}{
+		{"user:anne", true},
+		{"user:bob", false},
+		{"user:carol", false},
+	}
+	for _, tt := range tests {
+		allowed, err := fga.Check(context.Background(), tt.user, "can_publish", "document:plan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != tt.want {
+			t.Errorf("Check(%s can_publish document:plan) = %v, want %v", tt.user, allowed, tt.want)
+		}
+	}
+}
+
+func TestCheckTupleToUserset(t *testing.T) {
+	dsl, err := os.ReadFile("../model.fga")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fga := testutil.LoadFixture(t, string(dsl), []client.ClientTupleKey{
+		{User: "user:anne", Relation: "admin", Object: "organization:acme"},
+		{User: "user:bob", Relation: "member", Object: "organization:acme"},
+		{User: "organization:acme", Relation: "organization", Object: "project:roadmap"},
+	})
+	tests := []struct {
+		user, object string
+		want         bool
+	}{
+		{"user:anne", "project:roadmap", true},
+		{"user:bob", "project:roadmap", false},
+		{"user:anne", "project:other", false},
+	}
+	for _, tt := range tests {
+		allowed, err := fga.Check(context.Background(), tt.user, "admin", tt.object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != tt.want {
+			t.Errorf("Check(%s admin %s) = %v, want %v", tt.user, tt.object, allowed, tt.want)
+		}
+	}
+}
+
+func TestSimulateCheck(t *testing.T) {
+	fga := testutil.LoadFixture(t, `model
+  schema 1.1
+
+type user
+
+type organization
+  relations
+    define member: [user, user with non_expired]
+
+type project
+  relations
+    define organization: [organization]
+    define editor: [user] or member from organization
+
+condition non_expired(current_time: timestamp, valid_until: timestamp) {
+  current_time < valid_until
+}
+`, []client.ClientTupleKey{
+		{User: "organization:acme", Relation: "organization", Object: "project:roadmap"},
+	})
+	base := openfga.CheckRequestTupleKey{User: "user:anne", Relation: "editor", Object: "project:roadmap"}
+	// Would anne be an editor if she were made a member of acme until 2030?
+	membership := []client.ClientContextualTupleKey{{
+		User: "user:anne", Relation: "member", Object: "organization:acme",
+		Condition: &openfga.RelationshipCondition{
+			Name:    "non_expired",
+			Context: &map[string]interface{}{"valid_until": "2030-01-01T00:00:00Z"},
+		},
+	}}
+	tests := []struct {
+		name     string
+		extra    []client.ClientContextualTupleKey
+		checkCtx map[string]interface{}
+		want     bool
+	}{
+		{"before expiry", membership, map[string]interface{}{"current_time": "2026-10-14T09:00:00Z"}, true},
+		{"after expiry", membership, map[string]interface{}{"current_time": "2030-06-01T09:00:00Z"}, false},
+		{"not a member", nil, map[string]interface{}{"current_time": "2026-10-14T09:00:00Z"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := fga.SimulateCheck(context.Background(), base, tt.extra, tt.checkCtx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != tt.want {
+				t.Errorf("SimulateCheck = %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing parameter", func(t *testing.T) {
+		if _, err := fga.SimulateCheck(context.Background(), base, membership, nil); err == nil {
+			t.Error("SimulateCheck without current_time succeeded, want error")
+		}
+	})
+	// Nothing was written.
+	allowed, err := fga.Check(context.Background(), base.User, base.Relation, base.Object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed {
+		t.Error("Check after SimulateCheck = true, want false")
+	}
+}
+
+// TestListObjectsContext checks that the WithContext values reach the
+// server's list-objects request, against a server that lists a document
+// viewable only before 2030.
+func TestListObjectsContext(t *testing.T) {
+	const storeID, modelID = "01HVMMBCMGZNT3SED4Z17ECXCA", "01HVMMBCMGZNT3SED4Z17ECXCB"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/stores/"+storeID+"/list-objects" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Context map[string]interface{} `json:"context"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		now, ok := body.Context["current_time"].(string)
+		if !ok {
+			t.Errorf("list-objects request context = %v, want current_time", body.Context)
+		}
+		objects := []string{}
+		if ok && now < "2030" {
+			objects = append(objects, "document:plan")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"objects": objects})
+	}))
+	defer srv.Close()
+
+	fga, err := authz.New(authz.Config{APIURL: srv.URL, StoreID: storeID, ModelID: modelID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fga.Close()
+	tests := []struct {
+		now  string
+		want int
+	}{
+		{"2026-10-14T09:00:00Z", 1},
+		{"2030-06-01T09:00:00Z", 0},
+	}
+	for _, tt := range tests {
+		objects, err := fga.ListObjects(context.Background(), "user:anne", "viewer", "document",
+			authz.WithContext(map[string]interface{}{"current_time": tt.now}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objects) != tt.want {
+			t.Errorf("ListObjects at %s = %v, want %d objects", tt.now, objects, tt.want)
+		}
+	}
+}
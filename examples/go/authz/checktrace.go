@@ -0,0 +1,116 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// TracedChecker is implemented by FGA implementations that can ask the
+// server to trace how a Check was resolved.
+type TracedChecker interface {
+	CheckWithTrace(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error)
+}
+
+// CheckResult is the outcome of CheckWithTrace.
+type CheckResult struct {
+	Allowed bool
+	// Resolution is the server's trace of the path that resolved the check,
+	// listing the usersets it visited. It is empty when the transport
+	// cannot request a trace.
+	Resolution string
+	// Duration is the client-observed time of the call, retries included.
+	// The server does not report its datastore query count to clients; it
+	// is only available in the server's own logs and metrics.
+	Duration time.Duration
+}
+
+// CheckWithTrace is Check with server-side tracing enabled, for finding
+// checks that trigger deep userset expansions. Tracing costs the server
+// extra work, so use it for diagnosis rather than on every request. The
+// Check cache is bypassed. When the FGA implementation does not implement
+// TracedChecker the check runs untraced. As with Check, an empty tk.User
+// defaults to the user attached to ctx by WithUser.
+func (c *Client) CheckWithTrace(ctx context.Context, tk openfga.CheckRequestTupleKey, opts ...QueryOption) (_ CheckResult, err error) {
+	tk.User = userOrContext(ctx, tk.User)
+	ctx, span := c.startSpan(ctx, "Check", relationAttr(tk.Relation), objectTypeAttr(tk.Object))
+	defer func() { endSpan(span, err) }()
+
+	var res CheckResult
+	if _, err := ParseUser(tk.User); err != nil {
+		return res, fmt.Errorf("check: %w", c.redactErr(err, tk.User))
+	}
+	p := newQueryParams(opts)
+	modelID, err := c.queryModel(ctx, p)
+	if err != nil {
+		return res, err
+	}
+	setSpanModel(span, modelID)
+
+	check := c.fga.Check
+	if tc, ok := c.fga.(TracedChecker); ok {
+		check = tc.CheckWithTrace
+	}
+	start := time.Now()
+	var resp *client.ClientCheckResponse
+	err = c.call(ctx, "Check", func(ctx context.Context) (err error) {
+		resp, err = check(ctx, client.ClientCheckRequest{
+			User:             tk.User,
+			Relation:         tk.Relation,
+			Object:           tk.Object,
+			Context:          p.contextPtr(),
+			ContextualTuples: p.contextualTuples,
+		}, client.ClientCheckOptions{
+			AuthorizationModelId: modelID,
+			StoreId:              &c.StoreID,
+			Consistency:          p.consistency,
+		})
+		return c.redactErr(err, queryValues(p.contextualTuples, tk.User, tk.Object)...)
+	})
+	res.Duration = time.Since(start)
+	if err != nil {
+		return res, fmt.Errorf("check: %w", err)
+	}
+	res.Allowed = resp.GetAllowed()
+	res.Resolution = resp.GetResolution()
+	return res, nil
+}
+
+// checkRequest builds the API request body for a Check.
+func checkRequest(body client.ClientCheckRequest, opts client.ClientCheckOptions) openfga.CheckRequest {
+	return openfga.CheckRequest{
+		TupleKey:             openfga.CheckRequestTupleKey{User: body.User, Relation: body.Relation, Object: body.Object},
+		ContextualTuples:     contextualTupleKeys(body.ContextualTuples),
+		AuthorizationModelId: opts.AuthorizationModelId,
+		Context:              body.Context,
+		Consistency:          opts.Consistency,
+	}
+}
+
+// CheckWithTrace calls the API directly, as the SDK client does not expose
+// the trace flag.
+func (s sdkFGA) CheckWithTrace(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error) {
+	req := checkRequest(body, opts)
+	req.Trace = openfga.PtrBool(true)
+	resp, httpResp, err := s.c.OpenFgaApi.Check(ctx, deref(opts.StoreId)).Body(req).Execute()
+	return &client.ClientCheckResponse{CheckResponse: resp, HttpResponse: httpResp}, err
+}
+
+func (g *grpcFGA) CheckWithTrace(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error) {
+	var req openfgav1.CheckRequest
+	if err := toProto(checkRequest(body, opts), &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	req.Trace = true
+	resp, err := g.svc.Check(ctx, &req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	allowed, resolution := resp.GetAllowed(), resp.GetResolution()
+	return &client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed, Resolution: &resolution}}, nil
+}
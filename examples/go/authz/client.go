@@ -0,0 +1,385 @@
+// Package authz wraps the OpenFGA Go SDK with a small, store-scoped client
+// that the examples and downstream services can share.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openfga/go-sdk/client"
+	"github.com/openfga/go-sdk/credentials"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Config holds the settings needed to build a Client.
+type Config struct {
+	// APIURL is the OpenFGA HTTP endpoint, e.g. "http://localhost:8080",
+	// or the gRPC endpoint when Transport is TransportGRPC.
+	APIURL string
+	// Transport selects HTTP (the default) or gRPC.
+	Transport Transport
+	// StoreID selects an existing store. Leave empty and call CreateStore
+	// to provision a new one.
+	StoreID string
+	// ModelID pins an authorization model. When empty the client pins the
+	// store's latest model on first use; see Client.ModelID.
+	ModelID string
+	// Bootstrap, when set, provisions the store and model on the client's
+	// first call instead of requiring them to exist, as Client.Bootstrap
+	// does. Concurrent first calls wait for a single run. A failed run is
+	// returned to every call that waited on it and retried on the next
+	// call. Mutually exclusive with StoreID and ModelID.
+	Bootstrap *Manifest
+	// APIToken, when set, is sent as a Bearer token on every request. Use
+	// it for servers configured with pre-shared key authentication.
+	APIToken string
+	// ClientID, ClientSecret, TokenIssuer and Audience configure OAuth2
+	// client-credentials authentication, as used by OpenFGA Cloud and
+	// Auth0 FGA. Tokens are cached and refreshed shortly before they
+	// expire. Mutually exclusive with APIToken.
+	ClientID     string
+	ClientSecret string
+	TokenIssuer  string
+	Audience     string
+	// CheckConcurrency bounds the number of in-flight Checks issued by
+	// fan-out helpers such as BatchCheck. Defaults to 10.
+	CheckConcurrency int
+	// ListObjectsLimit is the server's list-objects result limit
+	// (OPENFGA_LIST_OBJECTS_MAX_RESULTS), which ListObjects uses to tell a
+	// truncated result from a complete one. Defaults to
+	// DefaultListObjectsLimit; negative disables the detection.
+	ListObjectsLimit int
+	// ValidateWrites checks every written tuple against the active model,
+	// or WriteOptions.ModelID when set, before sending it, reporting all
+	// offending tuples at once; see ValidateTuples.
+	ValidateWrites bool
+	// CheckCache caches Check results; see CacheConfig.
+	CheckCache CacheConfig
+	// DryRun makes every tuple write and delete a no-op: the request that
+	// would have been sent is logged at Info level instead, and Grant,
+	// GrantAtomic, Write and Revoke return it in a *DryRunError, which
+	// matches ErrDryRun. Checks, reads and model writes run normally.
+	DryRun bool
+	// Expiry names the condition used for time-bound grants; see
+	// SweepExpired.
+	Expiry ExpiryCondition
+	// TracerProvider, when set, receives a client span for each Check,
+	// ListObjects, Expand, Read and Write. Tracing is off when nil.
+	TracerProvider trace.TracerProvider
+	// Metrics observes every API call; see Metrics. Defaults to NopMetrics.
+	Metrics Metrics
+	// Logger receives diagnostics such as retries and model writes, with
+	// store_id, model_id and operation attributes. The client is silent
+	// when nil.
+	Logger *slog.Logger
+	// Redact, when set, is applied to every user and object the client
+	// puts in a log record or an error message, including tuples echoed
+	// back in the server's errors; see HashRedactor. Spans never carry
+	// object IDs. Errors keep the unredacted error in their chain for
+	// errors.As. Users and objects are logged as is when nil.
+	Redact Redactor
+	// DefaultTimeout bounds each API call, retries included, when the
+	// caller's context has no deadline. A caller's own deadline is always
+	// respected. Zero means no timeout.
+	DefaultTimeout time.Duration
+	// Retry controls retries of rate-limited and transiently failing calls.
+	// The zero value disables retries.
+	Retry RetryPolicy
+
+	// MaxIdleConns is the number of idle HTTP connections kept open to the
+	// server for reuse. Set it to at least the expected number of concurrent
+	// requests. Defaults to 100.
+	MaxIdleConns int
+	// MaxConnsPerHost bounds the number of HTTP connections to the server,
+	// idle or in use; requests beyond it wait for a free connection. Zero
+	// means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes idle connections after this long. Defaults to
+	// 90 seconds.
+	IdleConnTimeout time.Duration
+	// KeepAlive is the TCP keep-alive probe interval. Defaults to 30
+	// seconds; negative disables keep-alive probes.
+	KeepAlive time.Duration
+}
+
+// DefaultCheckConcurrency is used when Config.CheckConcurrency is unset.
+const DefaultCheckConcurrency = 10
+
+// DefaultListObjectsLimit is the OpenFGA server's default list-objects
+// result limit, used when Config.ListObjectsLimit is unset.
+const DefaultListObjectsLimit = 1000
+
+// Client is a thin wrapper around an FGA backend bound to a single store.
+type Client struct {
+	fga              FGA
+	checkConcurrency int
+	listLimit        int
+	retry            RetryPolicy
+	timeout          time.Duration
+	validate         bool
+	dryRun           bool
+	cache            *checkCache
+	expiry           ExpiryCondition
+	tracer           trace.Tracer
+	metrics          Metrics
+	log              *slog.Logger
+	redact           Redactor
+
+	closed *atomic.Bool
+	lazy   *lazyInit
+	// noBatchCheck is set once the server turns out to lack BatchCheck.
+	noBatchCheck *atomic.Bool
+
+	mu      sync.RWMutex
+	modelID string
+	models  map[string]*Model
+
+	// StoreID is the store every call is issued against.
+	StoreID string
+}
+
+// New builds the underlying SDK client from cfg, after checking it with
+// Validate.
+func New(cfg Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	creds := cfg.credentials()
+	if cfg.Transport == TransportGRPC {
+		g, err := newGRPCFGA(cfg, creds)
+		if err != nil {
+			return nil, fmt.Errorf("create OpenFGA gRPC client: %w", err)
+		}
+		return NewWithFGA(g, cfg), nil
+	}
+	httpClient, err := newHTTPClient(cfg, creds)
+	if err != nil {
+		return nil, fmt.Errorf("create OpenFGA client: %w", err)
+	}
+	sdk, err := client.NewSdkClient(&client.ClientConfiguration{
+		ApiUrl:     cfg.APIURL,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create OpenFGA client: %w", err)
+	}
+	return NewWithFGA(sdkFGA{c: sdk, http: httpClient, url: cfg.APIURL}, cfg), nil
+}
+
+// NewWithFGA builds a Client on top of an arbitrary FGA implementation.
+// Connection settings in cfg (APIURL and credentials) are ignored.
+func NewWithFGA(fga FGA, cfg Config) *Client {
+	concurrency := cfg.CheckConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCheckConcurrency
+	}
+	listLimit := cfg.ListObjectsLimit
+	if listLimit == 0 {
+		listLimit = DefaultListObjectsLimit
+	}
+	tp := cfg.TracerProvider
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = NopMetrics{}
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(discardHandler{})
+	}
+	var lazy *lazyInit
+	if cfg.Bootstrap != nil {
+		lazy = &lazyInit{manifest: *cfg.Bootstrap}
+	}
+	return &Client{
+		fga:              fga,
+		log:              logger,
+		redact:           cfg.Redact,
+		metrics:          metrics,
+		tracer:           tp.Tracer(tracerName),
+		checkConcurrency: concurrency,
+		listLimit:        listLimit,
+		retry:            cfg.Retry,
+		timeout:          cfg.DefaultTimeout,
+		validate:         cfg.ValidateWrites,
+		dryRun:           cfg.DryRun,
+		cache:            newCheckCache(cfg.CheckCache),
+		expiry:           cfg.Expiry.withDefaults(),
+		closed:           new(atomic.Bool),
+		noBatchCheck:     new(atomic.Bool),
+		lazy:             lazy,
+		modelID:          cfg.ModelID,
+		StoreID:          cfg.StoreID,
+	}
+}
+
+// ErrClosed is returned by calls made after Close.
+var ErrClosed = errors.New("authz: client closed")
+
+// Close drops cached Check results and releases the connections held by
+// the transport: idle HTTP connections, or the gRPC connection. Calls made
+// afterwards fail with ErrClosed, and closing again is a no-op. The
+// TracerProvider and Metrics are owned by the caller and are not shut
+// down. OAuth2 tokens are refreshed on demand, so there is no background
+// refresh to stop.
+func (c *Client) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if c.cache != nil {
+		c.cache.clear()
+	}
+	if cl, ok := c.fga.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+// ulid matches the IDs OpenFGA assigns to stores and models.
+var ulid = regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+
+// Validate reports the first problem with the connection settings in cfg,
+// so that a misconfigured client fails at startup rather than on its first
+// call: APIURL must be an http or https URL with a host, StoreID and
+// ModelID, when set, must be ULIDs, and the authentication fields must
+// describe exactly one method, with ClientSecret and TokenIssuer given
+// alongside ClientID.
+func (cfg Config) Validate() error {
+	u, err := url.Parse(cfg.APIURL)
+	switch {
+	case cfg.APIURL == "":
+		return fmt.Errorf("authz: APIURL is required, e.g. http://localhost:8080")
+	case err != nil:
+		return fmt.Errorf("authz: invalid APIURL %q: %w", cfg.APIURL, err)
+	case u.Scheme != "http" && u.Scheme != "https":
+		return fmt.Errorf("authz: invalid APIURL %q: scheme must be http or https", cfg.APIURL)
+	case u.Hostname() == "":
+		return fmt.Errorf("authz: invalid APIURL %q: missing host", cfg.APIURL)
+	}
+	if cfg.Transport != TransportHTTP && cfg.Transport != TransportGRPC {
+		return fmt.Errorf("authz: unknown Transport %d", cfg.Transport)
+	}
+	if cfg.StoreID != "" && !ulid.MatchString(cfg.StoreID) {
+		return fmt.Errorf("authz: invalid StoreID %q: expected a 26-character ULID such as 01HVMMBCMGZNT3SED4Z17ECXCA", cfg.StoreID)
+	}
+	if cfg.ModelID != "" && !ulid.MatchString(cfg.ModelID) {
+		return fmt.Errorf("authz: invalid ModelID %q: expected a 26-character ULID such as 01HVMMBCMGZNT3SED4Z17ECXCA", cfg.ModelID)
+	}
+	if cfg.Bootstrap != nil && (cfg.StoreID != "" || cfg.ModelID != "") {
+		return fmt.Errorf("authz: Bootstrap is mutually exclusive with StoreID and ModelID")
+	}
+	switch {
+	case cfg.APIToken != "" && cfg.ClientID != "":
+		return fmt.Errorf("authz: APIToken and ClientID are mutually exclusive")
+	case cfg.ClientID != "" && cfg.ClientSecret == "":
+		return fmt.Errorf("authz: ClientSecret is required with ClientID")
+	case cfg.ClientID != "" && cfg.TokenIssuer == "":
+		return fmt.Errorf("authz: TokenIssuer is required with ClientID")
+	case cfg.ClientID == "" && (cfg.ClientSecret != "" || cfg.TokenIssuer != "" || cfg.Audience != ""):
+		return fmt.Errorf("authz: ClientSecret, TokenIssuer and Audience require ClientID")
+	}
+	return nil
+}
+
+// credentials returns the SDK credentials implied by cfg, or nil for an
+// unauthenticated server. cfg must have passed Validate.
+func (cfg Config) credentials() *credentials.Credentials {
+	switch {
+	case cfg.ClientID != "":
+		return &credentials.Credentials{
+			Method: credentials.CredentialsMethodClientCredentials,
+			Config: &credentials.Config{
+				ClientCredentialsClientId:       cfg.ClientID,
+				ClientCredentialsClientSecret:   cfg.ClientSecret,
+				ClientCredentialsApiTokenIssuer: cfg.TokenIssuer,
+				ClientCredentialsApiAudience:    cfg.Audience,
+			},
+		}
+	case cfg.APIToken != "":
+		return &credentials.Credentials{
+			Method: credentials.CredentialsMethodApiToken,
+			Config: &credentials.Config{ApiToken: cfg.APIToken},
+		}
+	}
+	return nil
+}
+
+// ModelID returns the authorization model the client is pinned to, or ""
+// if none has been resolved yet.
+func (c *Client) ModelID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.modelID
+}
+
+// PinModel pins every subsequent query to the given model ID, e.g. to test
+// a migration against a model that is not the latest. An empty id unpins,
+// and the latest model is resolved again on the next query.
+func (c *Client) PinModel(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.modelID = id
+}
+
+// pinnedModel returns the pinned model ID as an SDK option, or nil to let
+// the server pick the latest model.
+func (c *Client) pinnedModel() *string {
+	return optional(c.ModelID())
+}
+
+// resolveModel returns the pinned model ID as an SDK option, pinning the
+// store's latest model first if none is set. Queries use it so results stay
+// reproducible even if another model is written concurrently.
+func (c *Client) resolveModel(ctx context.Context) (*string, error) {
+	if id := c.ModelID(); id != "" {
+		return &id, nil
+	}
+	id, err := c.LatestModelID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	pinned := c.modelID == ""
+	if pinned {
+		c.modelID = id
+	}
+	id = c.modelID
+	c.mu.Unlock()
+	if pinned && id != "" {
+		c.logger().Debug("pinned latest authorization model")
+	}
+	return optional(id), nil
+}
+
+// queryModel returns the model ID a query runs against: the one chosen
+// with WithModel, after checking that it exists, or the pinned model.
+func (c *Client) queryModel(ctx context.Context, p queryParams) (*string, error) {
+	if p.modelID == "" {
+		return c.resolveModel(ctx)
+	}
+	if _, err := c.readModel(ctx, p.modelID); err != nil {
+		return nil, err
+	}
+	id := p.modelID
+	return &id, nil
+}
+
+// optional returns nil for the empty string, for SDK fields that must be
+// omitted rather than sent empty.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
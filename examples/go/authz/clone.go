@@ -0,0 +1,178 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ModelLister is implemented by FGA implementations that can page through
+// every model of a store, newest first, as ReadAuthorizationModels does.
+type ModelLister interface {
+	ReadAuthorizationModels(ctx context.Context, opts client.ClientReadAuthorizationModelsOptions) (*client.ClientReadAuthorizationModelsResponse, error)
+}
+
+// CloneOptions configures CloneStore.
+type CloneOptions struct {
+	// AllModels copies the source's whole model history, oldest first, so
+	// that the clone's latest model matches the source's. By default only
+	// the latest model is copied. It requires an FGA implementation that
+	// implements ModelLister.
+	AllModels bool
+	// Concurrency bounds the tuple writes in flight; zero means
+	// Config.CheckConcurrency.
+	Concurrency int
+}
+
+// CloneStore creates a store called newName holding a copy of the models
+// and tuples of srcStoreID, and returns its ID. It is meant for giving each
+// test its own copy of a baseline store. The client's active store is not
+// changed.
+//
+// Tuples travel through ExportTuples and the ImportJSONLines reader, then
+// are written in chunks of MaxTuplesPerWrite with at most
+// opts.Concurrency chunks in flight. Model IDs are assigned by the server,
+// so the clone's IDs differ from the source's. If any step fails the new
+// store is deleted again.
+func (c *Client) CloneStore(ctx context.Context, srcStoreID, newName string, opts CloneOptions) (string, error) {
+	if srcStoreID == "" {
+		return "", fmt.Errorf("clone store: source store ID is required")
+	}
+	src := c.withStore(srcStoreID)
+	models, err := src.cloneModels(ctx, opts.AllModels)
+	if err != nil {
+		return "", fmt.Errorf("clone store %s: %w", srcStoreID, err)
+	}
+	var export bytes.Buffer
+	if _, err := src.ExportTuples(ctx, &export); err != nil {
+		return "", fmt.Errorf("clone store %s: %w", srcStoreID, err)
+	}
+	tuples, _, err := readJSONLTuples(&export)
+	if err != nil {
+		return "", fmt.Errorf("clone store %s: %w", srcStoreID, err)
+	}
+
+	dst := c.withStore("")
+	id, err := dst.CreateStore(ctx, newName)
+	if err != nil {
+		return "", fmt.Errorf("clone store %s: %w", srcStoreID, err)
+	}
+	if err := dst.fillClone(ctx, models, tuples, opts.Concurrency); err != nil {
+		// The clone is incomplete; failing to delete it leaves a partial
+		// store behind, which the caller can remove by name.
+		if derr := dst.DeleteStore(ctx, id); derr != nil {
+			c.logger().Warn("could not delete incomplete clone",
+				"operation", "CloneStore", "clone_store_id", id, "error", derr)
+		}
+		return "", fmt.Errorf("clone store %s: %w", srcStoreID, err)
+	}
+	c.logger().Info("cloned store", "operation", "CloneStore",
+		"source_store_id", srcStoreID, "clone_store_id", id, "models", len(models), "tuples", len(tuples))
+	return id, nil
+}
+
+// cloneModels returns the models to copy, oldest first: the latest one, or
+// with all set the whole history.
+func (c *Client) cloneModels(ctx context.Context, all bool) ([]openfga.AuthorizationModel, error) {
+	if !all {
+		m, err := c.latestModel(ctx)
+		if err != nil || m == nil {
+			return nil, err
+		}
+		return []openfga.AuthorizationModel{*m}, nil
+	}
+
+	lister, ok := c.fga.(ModelLister)
+	if !ok {
+		return nil, errors.New("copying all models needs an FGA implementation that implements ModelLister")
+	}
+	var (
+		models []openfga.AuthorizationModel
+		token  string
+	)
+	for {
+		var resp *client.ClientReadAuthorizationModelsResponse
+		err := c.call(ctx, "ReadAuthorizationModels", func(ctx context.Context) (err error) {
+			resp, err = lister.ReadAuthorizationModels(ctx, client.ClientReadAuthorizationModelsOptions{
+				StoreId:           &c.StoreID,
+				ContinuationToken: optional(token),
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read authorization models: %w", err)
+		}
+		models = append(models, resp.AuthorizationModels...)
+		token = deref(resp.ContinuationToken)
+		if token == "" {
+			break
+		}
+	}
+	// The server lists newest first; write them back in creation order.
+	for i, j := 0, len(models)-1; i < j; i, j = i+1, j-1 {
+		models[i], models[j] = models[j], models[i]
+	}
+	return models, nil
+}
+
+// fillClone writes models in order, then tuples against the last of them.
+func (c *Client) fillClone(ctx context.Context, models []openfga.AuthorizationModel, tuples []client.ClientTupleKey, concurrency int) error {
+	for _, m := range models {
+		var resp *client.ClientWriteAuthorizationModelResponse
+		err := c.call(ctx, "WriteAuthorizationModel", func(ctx context.Context) (err error) {
+			resp, err = c.fga.WriteAuthorizationModel(ctx, client.ClientWriteAuthorizationModelRequest{
+				SchemaVersion:   m.SchemaVersion,
+				TypeDefinitions: m.TypeDefinitions,
+				Conditions:      m.Conditions,
+			}, client.ClientWriteAuthorizationModelOptions{StoreId: &c.StoreID})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("write authorization model: %w", modelError(err))
+		}
+		c.PinModel(resp.AuthorizationModelId)
+	}
+
+	if concurrency <= 0 {
+		concurrency = c.checkConcurrency
+	}
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for _, chunk := range chunkTuples(tuples, MaxTuplesPerWrite) {
+		chunk := chunk
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return c.write(ctx, client.ClientWriteRequest{Writes: chunk}, "")
+		})
+	}
+	return g.Wait()
+}
+
+func (s sdkFGA) ReadAuthorizationModels(ctx context.Context, opts client.ClientReadAuthorizationModelsOptions) (*client.ClientReadAuthorizationModelsResponse, error) {
+	return s.c.ReadAuthorizationModels(ctx).Options(opts).Execute()
+}
+
+func (g *grpcFGA) ReadAuthorizationModels(ctx context.Context, opts client.ClientReadAuthorizationModelsOptions) (*client.ClientReadAuthorizationModelsResponse, error) {
+	req := &openfgav1.ReadAuthorizationModelsRequest{
+		StoreId:           deref(opts.StoreId),
+		ContinuationToken: deref(opts.ContinuationToken),
+	}
+	if opts.PageSize != nil {
+		req.PageSize = wrapperspb.Int32(*opts.PageSize)
+	}
+	resp, err := g.svc.ReadAuthorizationModels(ctx, req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientReadAuthorizationModelsResponse
+	return &out, fromProto(resp, &out)
+}
@@ -0,0 +1,141 @@
+package authz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// ModelDiff lists the differences between two sets of type definitions.
+// All lists are sorted by type and then relation.
+type ModelDiff struct {
+	AddedTypes       []string
+	RemovedTypes     []string
+	AddedRelations   []RelationDiff
+	RemovedRelations []RelationDiff
+	ChangedRelations []RelationDiff
+}
+
+// RelationDiff describes one relation in a ModelDiff. Old and New hold the
+// relation's rewrite in DSL form; Old is empty for an added relation and New
+// for a removed one.
+type RelationDiff struct {
+	Type     string
+	Relation string
+	Old      string
+	New      string
+}
+
+// Empty reports whether the two models were equivalent.
+func (d ModelDiff) Empty() bool {
+	return len(d.AddedTypes)+len(d.RemovedTypes)+len(d.AddedRelations)+
+		len(d.RemovedRelations)+len(d.ChangedRelations) == 0
+}
+
+// String renders d in a unified-diff style for code review:
+//
+//	+type team
+//	-type legacy
+//	+team#viewer: [user]
+//	-project#archived: [user]
+//	-project#viewer: [user]
+//	+project#viewer: [user, user:*]
+func (d ModelDiff) String() string {
+	var b strings.Builder
+	for _, t := range d.AddedTypes {
+		fmt.Fprintf(&b, "+type %s\n", t)
+	}
+	for _, t := range d.RemovedTypes {
+		fmt.Fprintf(&b, "-type %s\n", t)
+	}
+	for _, r := range d.AddedRelations {
+		fmt.Fprintf(&b, "+%s#%s: %s\n", r.Type, r.Relation, r.New)
+	}
+	for _, r := range d.RemovedRelations {
+		fmt.Fprintf(&b, "-%s#%s: %s\n", r.Type, r.Relation, r.Old)
+	}
+	for _, r := range d.ChangedRelations {
+		fmt.Fprintf(&b, "-%s#%s: %s\n+%s#%s: %s\n", r.Type, r.Relation, r.Old, r.Type, r.Relation, r.New)
+	}
+	return b.String()
+}
+
+// DiffModels compares two models independent of the order of types,
+// relations, type restrictions and union or intersection operands. A
+// relation whose rewrite changed is reported as changed rather than as a
+// removal plus an addition.
+func DiffModels(old, new []openfga.TypeDefinition) (ModelDiff, error) {
+	oldRels, err := renderedRelations(old)
+	if err != nil {
+		return ModelDiff{}, fmt.Errorf("old model: %w", err)
+	}
+	newRels, err := renderedRelations(new)
+	if err != nil {
+		return ModelDiff{}, fmt.Errorf("new model: %w", err)
+	}
+
+	var d ModelDiff
+	for _, typ := range sortedKeys(newRels) {
+		if _, ok := oldRels[typ]; !ok {
+			d.AddedTypes = append(d.AddedTypes, typ)
+		}
+	}
+	for _, typ := range sortedKeys(oldRels) {
+		if _, ok := newRels[typ]; !ok {
+			d.RemovedTypes = append(d.RemovedTypes, typ)
+		}
+	}
+	for _, typ := range sortedKeys(newRels) {
+		for _, rel := range sortedKeys(newRels[typ]) {
+			n := newRels[typ][rel]
+			o, ok := oldRels[typ][rel]
+			switch {
+			case !ok:
+				d.AddedRelations = append(d.AddedRelations, RelationDiff{Type: typ, Relation: rel, New: n})
+			case o != n:
+				d.ChangedRelations = append(d.ChangedRelations, RelationDiff{Type: typ, Relation: rel, Old: o, New: n})
+			}
+		}
+	}
+	for _, typ := range sortedKeys(oldRels) {
+		for _, rel := range sortedKeys(oldRels[typ]) {
+			if _, ok := newRels[typ][rel]; !ok {
+				d.RemovedRelations = append(d.RemovedRelations, RelationDiff{Type: typ, Relation: rel, Old: oldRels[typ][rel]})
+			}
+		}
+	}
+	return d, nil
+}
+
+// renderedRelations maps type -> relation -> canonical DSL rewrite.
+func renderedRelations(tds []openfga.TypeDefinition) (map[string]map[string]string, error) {
+	out := make(map[string]map[string]string, len(tds))
+	for _, td := range tds {
+		if _, dup := out[td.Type]; dup {
+			return nil, fmt.Errorf("duplicate type %q", td.Type)
+		}
+		ctd := canonicalTypeDef(td)
+		idx := indexTypeDefs([]openfga.TypeDefinition{ctd})
+		rels := map[string]string{}
+		for _, name := range relationNames(ctd) {
+			s, err := renderUserset((*ctd.Relations)[name], idx.directTypes(td.Type, name), false)
+			if err != nil {
+				return nil, fmt.Errorf("type %q relation %q: %w", td.Type, name, err)
+			}
+			rels[name] = s
+		}
+		out[td.Type] = rels
+	}
+	return out, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,698 @@
+package authz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// DSLError reports a problem in an authorization model DSL document.
+type DSLError struct {
+	Line int
+	Msg  string
+}
+
+func (e *DSLError) Error() string {
+	return fmt.Sprintf("dsl: line %d: %s", e.Line, e.Msg)
+}
+
+// ParseDSL converts a schema 1.1 model written in the OpenFGA DSL into the
+// type definitions, conditions and schema version expected by
+// WriteAuthorizationModel; the conditions are nil when the model declares
+// none. The server validates type restrictions such as "user with
+// non_expired" against the conditions sent in the same request, so the two
+// must be written together; ModelFromDSL builds that request.
+//
+// Supported relation rewrites are direct assignment ("[user, group#member]"),
+// computed usersets ("owner"), tuple-to-userset ("admin from parent"),
+// unions joined with "or", intersections joined with "and", and exclusions
+// ("[user] but not banned"). Operators cannot be mixed without parentheses.
+// Every relation and type a rewrite refers to must be defined in the model.
+//
+// Malformed input, including invalid UTF-8 and parentheses or type
+// arguments nested more than 32 deep, is reported as a *DSLError;
+// ParseDSL does not panic on any input.
+func ParseDSL(dsl string) ([]openfga.TypeDefinition, map[string]openfga.Condition, string, error) {
+	p, err := parseDSL(dsl)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	var conditions map[string]openfga.Condition
+	if len(p.conditions) > 0 {
+		conditions = p.conditions
+	}
+	return p.types, conditions, p.schema, nil
+}
+
+// maxDSLNesting bounds the nesting of parentheses in a rewrite and of type
+// arguments in a condition parameter type. Real models stay far below it;
+// the bound keeps the recursive parsers from degrading on hostile input.
+const maxDSLNesting = 32
+
+func parseDSL(dsl string) (*dslParser, error) {
+	p := &dslParser{}
+	lines := strings.Split(dsl, "\n")
+	for i, raw := range lines {
+		if !utf8.ValidString(raw) {
+			return nil, &DSLError{Line: i + 1, Msg: "invalid UTF-8"}
+		}
+		if err := p.line(i+1, raw); err != nil {
+			return nil, err
+		}
+	}
+	if p.cond != nil {
+		return nil, &DSLError{Line: p.condLine, Msg: fmt.Sprintf("condition %q: missing '}'", p.cond.Name)}
+	}
+	if p.schema == "" {
+		return nil, &DSLError{Line: 1, Msg: "missing schema declaration"}
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SchemaVersion is the model schema version this package reads and writes.
+// Schema 1.0 models are no longer accepted by OpenFGA, and 1.2 (modular
+// models) is not supported by ParseDSL.
+const SchemaVersion = "1.1"
+
+func checkSchemaVersion(v string) error {
+	switch v {
+	case SchemaVersion:
+		return nil
+	case "":
+		return fmt.Errorf("missing schema version")
+	}
+	return fmt.Errorf("unsupported schema version %q; only %s is supported", v, SchemaVersion)
+}
+
+// ModelFromDSL parses dsl into a write request. The schema version comes
+// from the DSL's schema line; schemaVersion may be empty, and otherwise
+// must agree with it.
+func ModelFromDSL(dsl, schemaVersion string) (client.ClientWriteAuthorizationModelRequest, error) {
+	p, err := parseDSL(dsl)
+	if err != nil {
+		return client.ClientWriteAuthorizationModelRequest{}, err
+	}
+	if schemaVersion != "" && schemaVersion != p.schema {
+		return client.ClientWriteAuthorizationModelRequest{}, fmt.Errorf("dsl: schema version %s conflicts with requested version %s", p.schema, schemaVersion)
+	}
+	model := client.ClientWriteAuthorizationModelRequest{
+		SchemaVersion:   p.schema,
+		TypeDefinitions: p.types,
+	}
+	if len(p.conditions) > 0 {
+		model.Conditions = &p.conditions
+	}
+	return model, nil
+}
+
+type dslParser struct {
+	schema string
+	types  []openfga.TypeDefinition
+
+	inModel     bool
+	inRelations bool
+	seenTypes   map[string]bool
+	// lines maps "type#relation" to the line defining it.
+	lines map[string]int
+
+	conditions map[string]openfga.Condition
+	// cond is the condition whose body is being read, opened on condLine;
+	// condBody holds its lines and condDepth the unclosed braces.
+	cond      *openfga.Condition
+	condLine  int
+	condBody  []string
+	condDepth int
+}
+
+// validate checks the references of every parsed relation once the whole
+// model is known, reporting the first dangling one at its define line.
+func (p *dslParser) validate() error {
+	idx := indexTypeDefs(p.types)
+	for _, td := range p.types {
+		for _, name := range relationNames(td) {
+			err := validateRelation(idx, td.Type, name)
+			for _, ref := range idx.directTypes(td.Type, name) {
+				if err == nil && ref.Condition != nil && p.conditions[*ref.Condition].Name == "" {
+					err = fmt.Errorf("type restriction %q references undefined condition %q", refKey(ref), *ref.Condition)
+				}
+			}
+			if err != nil {
+				return &DSLError{Line: p.lines[td.Type+"#"+name], Msg: fmt.Sprintf("relation %q: %v", name, err)}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *dslParser) line(n int, raw string) error {
+	text := stripComment(raw)
+	if p.cond != nil {
+		return p.conditionBody(n, text)
+	}
+	if text == "" {
+		return nil
+	}
+	fields := strings.Fields(text)
+	errorf := func(format string, args ...interface{}) error {
+		return &DSLError{Line: n, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	switch fields[0] {
+	case "model":
+		if len(fields) != 1 {
+			return errorf("unexpected tokens after model")
+		}
+		p.inModel = true
+		return nil
+	case "schema":
+		if !p.inModel {
+			return errorf("schema must follow model")
+		}
+		if len(fields) != 2 {
+			return errorf("expected schema <version>")
+		}
+		if p.schema != "" {
+			return errorf("duplicate schema declaration")
+		}
+		if err := checkSchemaVersion(fields[1]); err != nil {
+			return errorf("%v", err)
+		}
+		p.schema = fields[1]
+		return nil
+	case "type":
+		if p.schema == "" {
+			return errorf("type %q declared before schema", strings.Join(fields[1:], " "))
+		}
+		if len(fields) != 2 {
+			return errorf("expected type <name>")
+		}
+		name := fields[1]
+		if !isIdentifier(name) {
+			return errorf("invalid type name %q", name)
+		}
+		if p.seenTypes == nil {
+			p.seenTypes = map[string]bool{}
+		}
+		if p.seenTypes[name] {
+			return errorf("duplicate type %q", name)
+		}
+		p.seenTypes[name] = true
+		p.types = append(p.types, openfga.TypeDefinition{Type: name})
+		p.inRelations = false
+		return nil
+	case "relations":
+		if len(p.types) == 0 {
+			return errorf("relations outside of a type")
+		}
+		if len(fields) != 1 {
+			return errorf("unexpected tokens after relations")
+		}
+		p.inRelations = true
+		return nil
+	case "condition":
+		if p.schema == "" {
+			return errorf("condition declared before schema")
+		}
+		p.inRelations = false
+		return p.condition(n, strings.TrimSpace(strings.TrimPrefix(text, "condition")))
+	case "define":
+		if !p.inRelations {
+			return errorf("define outside of a relations block")
+		}
+		return p.define(n, strings.TrimSpace(strings.TrimPrefix(text, "define")))
+	}
+	return errorf("unexpected %q", fields[0])
+}
+
+func (p *dslParser) define(n int, body string) error {
+	errorf := func(format string, args ...interface{}) error {
+		return &DSLError{Line: n, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	name, expr, ok := strings.Cut(body, ":")
+	name = strings.TrimSpace(name)
+	if !ok {
+		return errorf("expected define <relation>: <rewrite>")
+	}
+	if !isIdentifier(name) {
+		return errorf("invalid relation name %q", name)
+	}
+
+	td := &p.types[len(p.types)-1]
+	if td.Relations == nil {
+		td.Relations = &map[string]openfga.Userset{}
+		td.Metadata = &openfga.Metadata{Relations: &map[string]openfga.RelationMetadata{}}
+	}
+	if _, dup := (*td.Relations)[name]; dup {
+		return errorf("duplicate relation %q on type %q", name, td.Type)
+	}
+
+	rw, direct, err := parseRewrite(expr)
+	if err != nil {
+		return errorf("relation %q: %v", name, err)
+	}
+	(*td.Relations)[name] = rw
+	(*td.Metadata.Relations)[name] = openfga.RelationMetadata{DirectlyRelatedUserTypes: &direct}
+	if p.lines == nil {
+		p.lines = map[string]int{}
+	}
+	p.lines[td.Type+"#"+name] = n
+	return nil
+}
+
+// condition parses a "condition name(param: type, ...) {" header, and the
+// body too if it closes on the same line.
+func (p *dslParser) condition(n int, header string) error {
+	errorf := func(format string, args ...interface{}) error {
+		return &DSLError{Line: n, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	name, rest, ok := strings.Cut(header, "(")
+	name = strings.TrimSpace(name)
+	if !ok {
+		return errorf("expected condition <name>(<parameters>) {")
+	}
+	if !isIdentifier(name) {
+		return errorf("invalid condition name %q", name)
+	}
+	if _, dup := p.conditions[name]; dup {
+		return errorf("duplicate condition %q", name)
+	}
+	list, rest, ok := strings.Cut(rest, ")")
+	if !ok {
+		return errorf("condition %q: missing ')'", name)
+	}
+	params, err := parseConditionParams(list)
+	if err != nil {
+		return errorf("condition %q: %v", name, err)
+	}
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "{") {
+		return errorf("condition %q: expected '{' after parameters", name)
+	}
+
+	p.cond = &openfga.Condition{Name: name, Parameters: &params}
+	p.condLine = n
+	p.condBody = nil
+	p.condDepth = 0
+	// A '#' straight after the brace starts a comment, as it would at the
+	// start of a body line.
+	return p.conditionBody(n, "{"+stripComment(rest[1:]))
+}
+
+// conditionBody adds a line to the expression of the open condition,
+// closing it at the '}' that balances its opening brace.
+func (p *dslParser) conditionBody(n int, text string) error {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			p.condDepth++
+		case '}':
+			if p.condDepth--; p.condDepth > 0 {
+				continue
+			}
+			if tail := strings.TrimSpace(text[i+1:]); tail != "" {
+				return &DSLError{Line: n, Msg: fmt.Sprintf("unexpected %q after condition %q", tail, p.cond.Name)}
+			}
+			p.condBody = append(p.condBody, text[:i])
+			return p.closeCondition()
+		}
+	}
+	p.condBody = append(p.condBody, text)
+	return nil
+}
+
+func (p *dslParser) closeCondition() error {
+	cond := *p.cond
+	// Drop the opening brace; it is the first character of the first line.
+	p.condBody[0] = p.condBody[0][1:]
+	var lines []string
+	for _, l := range p.condBody {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	cond.Expression = strings.Join(lines, "\n")
+	if cond.Expression == "" {
+		return &DSLError{Line: p.condLine, Msg: fmt.Sprintf("condition %q: empty expression", cond.Name)}
+	}
+	if p.conditions == nil {
+		p.conditions = map[string]openfga.Condition{}
+	}
+	p.conditions[cond.Name] = cond
+	p.cond = nil
+	return nil
+}
+
+// conditionTypes maps the parameter types of the DSL to the API's type
+// names. map and list take a single type argument, e.g. list<string>.
+var conditionTypes = map[string]openfga.TypeName{
+	"any":       openfga.TYPENAME_ANY,
+	"bool":      openfga.TYPENAME_BOOL,
+	"string":    openfga.TYPENAME_STRING,
+	"int":       openfga.TYPENAME_INT,
+	"uint":      openfga.TYPENAME_UINT,
+	"double":    openfga.TYPENAME_DOUBLE,
+	"duration":  openfga.TYPENAME_DURATION,
+	"timestamp": openfga.TYPENAME_TIMESTAMP,
+	"ipaddress": openfga.TYPENAME_IPADDRESS,
+	"map":       openfga.TYPENAME_MAP,
+	"list":      openfga.TYPENAME_LIST,
+}
+
+// parseConditionParams parses "current_time: timestamp, ips: list<ipaddress>".
+func parseConditionParams(list string) (map[string]openfga.ConditionParamTypeRef, error) {
+	params := map[string]openfga.ConditionParamTypeRef{}
+	if strings.TrimSpace(list) == "" {
+		return nil, fmt.Errorf("no parameters")
+	}
+	for _, part := range strings.Split(list, ",") {
+		name, typ, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok {
+			return nil, fmt.Errorf("expected <name>: <type>, got %q", strings.TrimSpace(part))
+		}
+		if !isIdentifier(name) {
+			return nil, fmt.Errorf("invalid parameter name %q", name)
+		}
+		if _, dup := params[name]; dup {
+			return nil, fmt.Errorf("duplicate parameter %q", name)
+		}
+		ref, err := parseConditionType(strings.TrimSpace(typ))
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %v", name, err)
+		}
+		params[name] = ref
+	}
+	return params, nil
+}
+
+func parseConditionType(s string) (openfga.ConditionParamTypeRef, error) {
+	if strings.Count(s, "<") > maxDSLNesting {
+		return openfga.ConditionParamTypeRef{}, fmt.Errorf("type %q: type arguments nested deeper than %d", s, maxDSLNesting)
+	}
+	base, arg, generic := strings.Cut(s, "<")
+	tn, ok := conditionTypes[strings.TrimSpace(base)]
+	if !ok {
+		return openfga.ConditionParamTypeRef{}, fmt.Errorf("unknown type %q; supported types are %s", s, conditionTypeList())
+	}
+	isGeneric := tn == openfga.TYPENAME_MAP || tn == openfga.TYPENAME_LIST
+	switch {
+	case isGeneric && !generic:
+		return openfga.ConditionParamTypeRef{}, fmt.Errorf("type %q needs a type argument, e.g. %s<string>", s, base)
+	case !isGeneric && generic:
+		return openfga.ConditionParamTypeRef{}, fmt.Errorf("type %q takes no type argument", s)
+	case !generic:
+		return openfga.ConditionParamTypeRef{TypeName: tn}, nil
+	}
+	if !strings.HasSuffix(arg, ">") {
+		return openfga.ConditionParamTypeRef{}, fmt.Errorf("type %q: missing '>'", s)
+	}
+	elem, err := parseConditionType(strings.TrimSpace(strings.TrimSuffix(arg, ">")))
+	if err != nil {
+		return openfga.ConditionParamTypeRef{}, err
+	}
+	return openfga.ConditionParamTypeRef{TypeName: tn, GenericTypes: &[]openfga.ConditionParamTypeRef{elem}}, nil
+}
+
+func conditionTypeList() string {
+	names := make([]string, 0, len(conditionTypes))
+	for name := range conditionTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// parseRewrite parses the right-hand side of a define. It returns the userset
+// rewrite and the directly related user types from the bracketed portion.
+func parseRewrite(expr string) (openfga.Userset, []openfga.RelationReference, error) {
+	toks, err := tokenize(expr)
+	if err != nil {
+		return openfga.Userset{}, nil, err
+	}
+	if len(toks) == 0 {
+		return openfga.Userset{}, nil, fmt.Errorf("empty rewrite")
+	}
+	direct := []openfga.RelationReference{}
+	rw, err := parseExpr(toks, &direct)
+	if err != nil {
+		return openfga.Userset{}, nil, err
+	}
+	return rw, direct, nil
+}
+
+// parseExpr parses operands joined by a single kind of operator. As in the
+// OpenFGA DSL, operators cannot be mixed without parentheses, and "but not"
+// takes exactly two operands. The type restrictions of the one permitted
+// direct assignment are stored in direct.
+func parseExpr(toks []string, direct *[]openfga.RelationReference) (openfga.Userset, error) {
+	operands, op, err := splitOperator(toks)
+	if err != nil {
+		return openfga.Userset{}, err
+	}
+	children := make([]openfga.Userset, len(operands))
+	for i, operand := range operands {
+		if children[i], err = parseOperand(operand, direct); err != nil {
+			return openfga.Userset{}, err
+		}
+	}
+	switch op {
+	case "":
+		return children[0], nil
+	case "but not":
+		if len(children) != 2 {
+			return openfga.Userset{}, fmt.Errorf("\"but not\" cannot be chained; use parentheses")
+		}
+		return openfga.Userset{Difference: &openfga.Difference{Base: children[0], Subtract: children[1]}}, nil
+	case "and":
+		return openfga.Userset{Intersection: &openfga.Usersets{Child: children}}, nil
+	}
+	return openfga.Userset{Union: &openfga.Usersets{Child: children}}, nil
+}
+
+// parseOperand parses a parenthesized expression or a single term.
+func parseOperand(toks []string, direct *[]openfga.RelationReference) (openfga.Userset, error) {
+	if len(toks) > 0 && toks[0] == "(" {
+		if toks[len(toks)-1] != ")" {
+			return openfga.Userset{}, fmt.Errorf("unexpected %q after ')'", toks[len(toks)-1])
+		}
+		return parseExpr(toks[1:len(toks)-1], direct)
+	}
+	us, refs, err := parseTerm(toks)
+	if err != nil {
+		return openfga.Userset{}, err
+	}
+	if refs != nil {
+		if len(*direct) > 0 {
+			return openfga.Userset{}, fmt.Errorf("more than one direct assignment")
+		}
+		*direct = refs
+	}
+	return us, nil
+}
+
+// splitOperator splits toks on the operator joining them outside of
+// parentheses, returning the operands and the operator, or "" for a single
+// operand.
+func splitOperator(toks []string) ([][]string, string, error) {
+	var (
+		operands [][]string
+		op       string
+		depth    int
+		start    int
+	)
+	for i := 0; i < len(toks); i++ {
+		var found string
+		switch t := toks[i]; {
+		case t == "(":
+			depth++
+		case t == ")":
+			if depth--; depth < 0 {
+				return nil, "", fmt.Errorf("unexpected ')'")
+			}
+		case depth > 0:
+		case t == "or" || t == "and":
+			found = t
+		case t == "but" && i+1 < len(toks) && toks[i+1] == "not":
+			found = "but not"
+		}
+		if found == "" {
+			continue
+		}
+		if op != "" && op != found {
+			return nil, "", fmt.Errorf("cannot mix %q and %q without parentheses", op, found)
+		}
+		op = found
+		operands = append(operands, toks[start:i])
+		i += len(strings.Fields(found)) - 1
+		start = i + 1
+	}
+	if depth > 0 {
+		return nil, "", fmt.Errorf("unterminated '('")
+	}
+	return append(operands, toks[start:]), op, nil
+}
+
+// parseTerm parses a single operand: a bracketed direct assignment, a
+// computed relation, or "relation from tupleset".
+func parseTerm(toks []string) (openfga.Userset, []openfga.RelationReference, error) {
+	switch {
+	case len(toks) == 0:
+		return openfga.Userset{}, nil, fmt.Errorf("missing operand")
+	case len(toks) == 1 && strings.HasPrefix(toks[0], "["):
+		refs, err := parseDirect(toks[0])
+		if err != nil {
+			return openfga.Userset{}, nil, err
+		}
+		return openfga.Userset{This: &map[string]interface{}{}}, refs, nil
+	case len(toks) == 1:
+		if !isIdentifier(toks[0]) {
+			return openfga.Userset{}, nil, fmt.Errorf("invalid relation reference %q", toks[0])
+		}
+		return computed(toks[0]), nil, nil
+	case len(toks) == 3 && toks[1] == "from":
+		if !isIdentifier(toks[0]) || !isIdentifier(toks[2]) {
+			return openfga.Userset{}, nil, fmt.Errorf("invalid tuple-to-userset %q", strings.Join(toks, " "))
+		}
+		return tupleToUserset(toks[0], toks[2]), nil, nil
+	}
+	return openfga.Userset{}, nil, fmt.Errorf("unexpected %q", strings.Join(toks, " "))
+}
+
+// parseDirect parses "[user, user:*, group#member, user with cond]".
+func parseDirect(block string) ([]openfga.RelationReference, error) {
+	inner := strings.TrimSpace(block[1 : len(block)-1])
+	if inner == "" {
+		return nil, fmt.Errorf("empty direct assignment")
+	}
+	var refs []openfga.RelationReference
+	for _, part := range strings.Split(inner, ",") {
+		ref, err := parseRelationReference(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+func parseRelationReference(s string) (openfga.RelationReference, error) {
+	var ref openfga.RelationReference
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 1:
+	case len(fields) == 3 && fields[1] == "with":
+		if !isIdentifier(fields[2]) {
+			return ref, fmt.Errorf("invalid condition name %q", fields[2])
+		}
+		cond := fields[2]
+		ref.Condition = &cond
+	default:
+		return ref, fmt.Errorf("invalid type restriction %q", s)
+	}
+
+	name := fields[0]
+	switch {
+	case strings.HasSuffix(name, ":*"):
+		ref.Type = strings.TrimSuffix(name, ":*")
+		ref.Wildcard = &map[string]interface{}{}
+	case strings.Contains(name, "#"):
+		typ, rel, _ := strings.Cut(name, "#")
+		if !isIdentifier(rel) {
+			return ref, fmt.Errorf("invalid type restriction %q", s)
+		}
+		ref.Type = typ
+		ref.Relation = &rel
+	default:
+		ref.Type = name
+	}
+	if !isIdentifier(ref.Type) {
+		return ref, fmt.Errorf("invalid type restriction %q", s)
+	}
+	return ref, nil
+}
+
+// tokenize splits a rewrite into words and parentheses, keeping bracketed
+// blocks intact. It rejects parentheses nested deeper than maxDSLNesting.
+func tokenize(expr string) ([]string, error) {
+	var (
+		toks  []string
+		depth int
+	)
+	for i := 0; i < len(expr); {
+		switch c := expr[i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated '['")
+			}
+			toks = append(toks, expr[i:i+end+1])
+			i += end + 1
+		case c == ']':
+			return nil, fmt.Errorf("unexpected ']'")
+		case c == '(' || c == ')':
+			if c == ')' {
+				depth--
+			} else if depth++; depth > maxDSLNesting {
+				return nil, fmt.Errorf("parentheses nested deeper than %d", maxDSLNesting)
+			}
+			toks = append(toks, expr[i:i+1])
+			i++
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\r[]()", rune(expr[j])) {
+				j++
+			}
+			toks = append(toks, expr[i:j])
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+// stripComment removes a trailing "# ..." comment and surrounding space. A
+// '#' inside a userset reference such as "group#member" is not a comment.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			line = line[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func computed(relation string) openfga.Userset {
+	return openfga.Userset{ComputedUserset: &openfga.ObjectRelation{Relation: &relation}}
+}
+
+func tupleToUserset(relation, tupleset string) openfga.Userset {
+	return openfga.Userset{TupleToUserset: &openfga.TupleToUserset{
+		Tupleset:        openfga.ObjectRelation{Relation: &tupleset},
+		ComputedUserset: openfga.ObjectRelation{Relation: &relation},
+	}}
+}
@@ -0,0 +1,52 @@
+package authz
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+func FuzzParseDSL(f *testing.F) {
+	for _, m := range exampleModels {
+		dsl, err := os.ReadFile(m.path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(dsl))
+	}
+	f.Add("model\n  schema 1.1\ntype user\ntype doc\n  relations\n    define viewer: " + strings.Repeat("(", 40) + "[user]" + strings.Repeat(")", 40))
+	f.Add("model\n  schema 1.1\ntype doc\n  relations\n    define viewer: (([user])\n")
+	f.Add("model\n  schema 1.1\ncondition c(x: list<list<map<string>>>) {\n  x == x\n")
+	f.Add("model\n  schema 1.1\ntype \xff\n")
+	f.Add("type user\n")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, dsl string) {
+		types, conditions, schema, err := ParseDSL(dsl)
+		if err != nil {
+			var dslErr *DSLError
+			if !errors.As(err, &dslErr) {
+				t.Fatalf("ParseDSL error %v (%T) is not a *DSLError", err, err)
+			}
+			return
+		}
+		model := client.ClientWriteAuthorizationModelRequest{SchemaVersion: schema, TypeDefinitions: types}
+		if conditions != nil {
+			model.Conditions = &conditions
+		}
+		rendered, err := RenderModel(model)
+		if err != nil {
+			t.Fatalf("RenderModel of a parsed model: %v\ninput:\n%s", err, dsl)
+		}
+		reparsed, err := ModelFromDSL(rendered, "")
+		if err != nil {
+			t.Fatalf("parse rendered model: %v\ninput:\n%s\nrendered:\n%s", err, dsl, rendered)
+		}
+		if !modelsEqual(model, reparsed) {
+			t.Fatalf("model changed in round trip\ninput:\n%s\nrendered:\n%s", dsl, rendered)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoAPIURL is returned by ConfigFromEnv when no API endpoint is set.
+var ErrNoAPIURL = errors.New("authz: FGA_API_URL or FGA_API_SCHEME and FGA_API_HOST must be set")
+
+// ConfigFromEnv builds a Config from the environment variables used by the
+// OpenFGA CLI:
+//
+//	FGA_API_URL                          e.g. http://localhost:8080
+//	FGA_API_SCHEME, FGA_API_HOST         alternative to FGA_API_URL
+//	FGA_STORE_ID, FGA_MODEL_ID
+//	FGA_API_TOKEN                        pre-shared key authentication
+//	FGA_CLIENT_ID, FGA_CLIENT_SECRET,
+//	FGA_API_TOKEN_ISSUER, FGA_API_AUDIENCE  client-credentials authentication
+//
+// If no endpoint is configured the remaining fields are still populated and
+// ErrNoAPIURL is returned, so callers can fall back to a default endpoint.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		APIURL:       os.Getenv("FGA_API_URL"),
+		StoreID:      os.Getenv("FGA_STORE_ID"),
+		ModelID:      os.Getenv("FGA_MODEL_ID"),
+		APIToken:     os.Getenv("FGA_API_TOKEN"),
+		ClientID:     os.Getenv("FGA_CLIENT_ID"),
+		ClientSecret: os.Getenv("FGA_CLIENT_SECRET"),
+		TokenIssuer:  os.Getenv("FGA_API_TOKEN_ISSUER"),
+		Audience:     os.Getenv("FGA_API_AUDIENCE"),
+	}
+	if cfg.APIURL != "" {
+		return cfg, nil
+	}
+
+	scheme, host := os.Getenv("FGA_API_SCHEME"), os.Getenv("FGA_API_HOST")
+	switch {
+	case scheme == "" && host == "":
+		return cfg, ErrNoAPIURL
+	case scheme == "":
+		return cfg, fmt.Errorf("authz: FGA_API_SCHEME is required with FGA_API_HOST")
+	case host == "":
+		return cfg, fmt.Errorf("authz: FGA_API_HOST is required with FGA_API_SCHEME")
+	}
+	cfg.APIURL = scheme + "://" + host
+	return cfg, nil
+}
@@ -0,0 +1,174 @@
+package authz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	openfga "github.com/openfga/go-sdk"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/status"
+)
+
+// Errors returned by Client methods match one of these with errors.Is when
+// the server's answer allows it. The original SDK or transport error stays
+// in the chain, so errors.As still finds it, e.g. to read the message of an
+// openfga.FgaApiValidationError.
+var (
+	// ErrStoreNotFound means the store does not exist, e.g. because it was
+	// deleted; callers may recreate it.
+	ErrStoreNotFound = errors.New("authz: store not found")
+	// ErrModelNotFound means the pinned authorization model does not exist
+	// in the store, or the store has no model yet.
+	ErrModelNotFound = errors.New("authz: authorization model not found")
+	// ErrValidation means the server rejected the request as invalid, e.g.
+	// a tuple the model does not allow or a malformed model.
+	ErrValidation = errors.New("authz: invalid request")
+	// ErrUnauthorized means the server rejected the client's credentials,
+	// or no OAuth2 token could be obtained.
+	ErrUnauthorized = errors.New("authz: unauthorized")
+	// ErrRateLimited means the server throttled the call and it was not
+	// retried, or every retry was throttled too.
+	ErrRateLimited = errors.New("authz: rate limited")
+)
+
+// classifyError wraps err with the sentinel matching its status code and
+// OpenFGA error code. Errors that match none are returned unchanged.
+func classifyError(err error) error {
+	kind := errorKind(err)
+	if kind == nil || errors.Is(err, kind) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", kind, err)
+}
+
+func errorKind(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.As(err, new(*oauth2.RetrieveError)) {
+		return ErrUnauthorized
+	}
+	switch responseCode(err) {
+	case string(openfga.NOTFOUNDERRORCODE_STORE_ID_NOT_FOUND):
+		return ErrStoreNotFound
+	case string(openfga.ERRORCODE_AUTHORIZATION_MODEL_NOT_FOUND), string(openfga.ERRORCODE_LATEST_AUTHORIZATION_MODEL_NOT_FOUND):
+		return ErrModelNotFound
+	}
+	var sc statusCoder
+	if !errors.As(err, &sc) {
+		return nil
+	}
+	switch sc.ResponseStatusCode() {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// responseCode returns the OpenFGA error code carried by err, such as
+// "store_id_not_found", or "" if it has none.
+func responseCode(err error) string {
+	var (
+		verr openfga.FgaApiValidationError
+		nerr openfga.FgaApiNotFoundError
+		gerr *grpcError
+	)
+	switch {
+	case errors.As(err, &verr):
+		return string(verr.ResponseCode())
+	case errors.As(err, &nerr):
+		return string(nerr.ResponseCode())
+	case errors.As(err, &gerr):
+		return gerr.reason
+	}
+	return ""
+}
+
+// responseMessage returns the message of the server's error body, or "" if
+// err carries none.
+func responseMessage(err error) string {
+	var (
+		verr openfga.FgaApiValidationError
+		gerr *grpcError
+	)
+	switch {
+	case errors.As(err, &verr):
+		var body openfga.ValidationErrorMessageResponse
+		if json.Unmarshal(verr.Body(), &body) == nil {
+			return body.GetMessage()
+		}
+	case errors.As(err, &gerr):
+		return status.Convert(gerr.err).Message()
+	}
+	return ""
+}
+
+// ModelValidationError is returned when the server rejects an authorization
+// model, e.g. because a relation references an undefined type. It decodes
+// the server's diagnostics: TypeName and Relation name the definition at
+// fault when the message identifies one. It matches ErrValidation, and the
+// SDK or transport error stays in its chain.
+type ModelValidationError struct {
+	// Code is the OpenFGA error code, e.g. "invalid_authorization_model".
+	Code     string
+	TypeName string
+	Relation string
+	Message  string
+
+	err error
+}
+
+func (e *ModelValidationError) Error() string {
+	switch {
+	case e.Relation != "":
+		return fmt.Sprintf("authz: invalid model: %s#%s: %s", e.TypeName, e.Relation, e.Message)
+	case e.TypeName != "":
+		return fmt.Sprintf("authz: invalid model: type %s: %s", e.TypeName, e.Message)
+	}
+	return "authz: invalid model: " + e.Message
+}
+
+func (e *ModelValidationError) Unwrap() error { return e.err }
+
+// modelErrorPaths match the server's messages that name the definition at
+// fault, most specific first.
+var modelErrorPaths = []*regexp.Regexp{
+	regexp.MustCompile(`relation '(?P<relation>[^']+)' in object type '(?P<type>[^']+)'`),
+	regexp.MustCompile(`on '(?P<relation>[^']+)' in object type '(?P<type>[^']+)'`),
+	regexp.MustCompile(`'(?P<type>[^'#]+)#(?P<relation>[^']+)' relation is undefined`),
+	regexp.MustCompile(`type '(?P<type>[^']+)'`),
+	regexp.MustCompile(`'(?P<type>[^']+)' is an undefined object type`),
+}
+
+// modelError returns err as a *ModelValidationError if the server rejected
+// the model as invalid, and unchanged otherwise.
+func modelError(err error) error {
+	if !errors.Is(err, ErrValidation) {
+		return err
+	}
+	e := &ModelValidationError{Code: responseCode(err), Message: responseMessage(err), err: err}
+	if e.Message == "" {
+		e.Message = err.Error()
+	}
+	for _, re := range modelErrorPaths {
+		m := re.FindStringSubmatch(e.Message)
+		if m == nil {
+			continue
+		}
+		if i := re.SubexpIndex("type"); i > 0 {
+			e.TypeName = m[i]
+		}
+		if i := re.SubexpIndex("relation"); i > 0 {
+			e.Relation = m[i]
+		}
+		break
+	}
+	return e
+}
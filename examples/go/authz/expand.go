@@ -0,0 +1,94 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// Expand returns the userset tree the server resolves for relation on
+// object. It is a debugging aid for understanding why a Check was denied.
+func (c *Client) Expand(ctx context.Context, relation, object string) (_ *openfga.UsersetTree, err error) {
+	ctx, span := c.startSpan(ctx, "Expand", relationAttr(relation), objectTypeAttr(object))
+	defer func() { endSpan(span, err) }()
+
+	modelID, err := c.resolveModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	setSpanModel(span, modelID)
+	var resp *client.ClientExpandResponse
+	err = c.call(ctx, "Expand", func(ctx context.Context) (err error) {
+		resp, err = c.fga.Expand(ctx, client.ClientExpandRequest{
+			Relation: relation,
+			Object:   object,
+		}, client.ClientExpandOptions{
+			AuthorizationModelId: modelID,
+			StoreId:              &c.StoreID,
+		})
+		return c.redactErr(err, object)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("expand: %w", err)
+	}
+	return resp.Tree, nil
+}
+
+// FormatExpandTree renders tree as an indented text outline, one node per
+// line, suitable for pasting into a ticket:
+//
+//	project:api#viewer union
+//	  project:api#viewer users
+//	    user:alice
+//	  project:api#viewer computed project:api#editor
+func FormatExpandTree(tree *openfga.UsersetTree) string {
+	if tree == nil || tree.Root == nil {
+		return "(empty)\n"
+	}
+	var b strings.Builder
+	formatNode(&b, *tree.Root, 0)
+	return b.String()
+}
+
+func formatNode(b *strings.Builder, n openfga.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch {
+	case n.Leaf != nil && n.Leaf.Users != nil:
+		fmt.Fprintf(b, "%s%s users\n", indent, n.Name)
+		if len(n.Leaf.Users.Users) == 0 {
+			fmt.Fprintf(b, "%s  (none)\n", indent)
+		}
+		for _, u := range n.Leaf.Users.Users {
+			fmt.Fprintf(b, "%s  %s\n", indent, u)
+		}
+	case n.Leaf != nil && n.Leaf.Computed != nil:
+		fmt.Fprintf(b, "%s%s computed %s\n", indent, n.Name, n.Leaf.Computed.Userset)
+	case n.Leaf != nil && n.Leaf.TupleToUserset != nil:
+		ttu := n.Leaf.TupleToUserset
+		fmt.Fprintf(b, "%s%s tuple-to-userset %s\n", indent, n.Name, ttu.Tupleset)
+		for _, comp := range ttu.Computed {
+			fmt.Fprintf(b, "%s  %s\n", indent, comp.Userset)
+		}
+	case n.Union != nil:
+		fmt.Fprintf(b, "%s%s union\n", indent, n.Name)
+		for _, child := range n.Union.Nodes {
+			formatNode(b, child, depth+1)
+		}
+	case n.Intersection != nil:
+		fmt.Fprintf(b, "%s%s intersection\n", indent, n.Name)
+		for _, child := range n.Intersection.Nodes {
+			formatNode(b, child, depth+1)
+		}
+	case n.Difference != nil:
+		fmt.Fprintf(b, "%s%s difference\n", indent, n.Name)
+		fmt.Fprintf(b, "%s  base\n", indent)
+		formatNode(b, n.Difference.Base, depth+2)
+		fmt.Fprintf(b, "%s  subtract\n", indent)
+		formatNode(b, n.Difference.Subtract, depth+2)
+	default:
+		fmt.Fprintf(b, "%s%s\n", indent, n.Name)
+	}
+}
@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// Defaults for ExpiryCondition, matching a model condition such as
+//
+//	condition not_expired(current_time: timestamp, valid_until: timestamp) {
+//	  current_time < valid_until
+//	}
+const (
+	DefaultExpiryCondition = "not_expired"
+	DefaultExpiryField     = "valid_until"
+)
+
+// ExpiryCondition identifies the time-bound grants SweepExpired removes:
+// tuples carrying the condition Name whose stored context holds an RFC 3339
+// timestamp under Field.
+type ExpiryCondition struct {
+	// Name is the model condition. Defaults to DefaultExpiryCondition.
+	Name string
+	// Field is the context parameter holding the expiry time. Defaults to
+	// DefaultExpiryField.
+	Field string
+}
+
+func (e ExpiryCondition) withDefaults() ExpiryCondition {
+	if e.Name == "" {
+		e.Name = DefaultExpiryCondition
+	}
+	if e.Field == "" {
+		e.Field = DefaultExpiryField
+	}
+	return e
+}
+
+// expiresAt returns the expiry stored with t, or false if t is not a
+// time-bound grant of this kind.
+func (e ExpiryCondition) expiresAt(t openfga.TupleKey) (time.Time, bool) {
+	if t.Condition == nil || t.Condition.Name != e.Name || t.Condition.Context == nil {
+		return time.Time{}, false
+	}
+	s, ok := (*t.Condition.Context)[e.Field].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	at, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// SweepExpired deletes the time-bound grants (see Config.Expiry) that
+// expired at or before now and returns how many were removed. Expired
+// tuples already deny access; sweeping keeps them from accumulating. The
+// whole store is read, and deletes are sent in chunks of MaxTuplesPerWrite,
+// so a failure part-way leaves earlier chunks deleted and the returned count
+// reflects them. Tuples whose expiry field is missing or not a timestamp
+// are left alone. In dry-run mode the count is of tuples that would have
+// been deleted.
+func (c *Client) SweepExpired(ctx context.Context, now time.Time) (int, error) {
+	var expired []client.ClientTupleKeyWithoutCondition
+	err := c.readPages(ctx, client.ClientReadRequest{}, func(page []openfga.Tuple, _ bool) bool {
+		for _, t := range page {
+			if at, ok := c.expiry.expiresAt(t.Key); ok && !at.After(now) {
+				expired = append(expired, client.ClientTupleKeyWithoutCondition{
+					User:     t.Key.User,
+					Relation: t.Key.Relation,
+					Object:   t.Key.Object,
+				})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("sweep expired: %w", err)
+	}
+
+	var removed int
+	for _, chunk := range chunkTuples(expired, MaxTuplesPerWrite) {
+		if err := c.write(ctx, client.ClientWriteRequest{Deletes: chunk}, ""); err != nil {
+			return removed, fmt.Errorf("sweep expired: %w", err)
+		}
+		removed += len(chunk)
+	}
+	if removed > 0 {
+		c.logger().Info("swept expired tuples", "count", removed)
+	}
+	return removed, nil
+}
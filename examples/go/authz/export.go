@@ -0,0 +1,220 @@
+package authz
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// exportRunSize is the number of tuple keys ExportTuples sorts in memory
+// before spilling them to a temporary file.
+var exportRunSize = 100_000
+
+// ExportTuples writes every tuple in the store to w as JSON-lines, one tuple
+// key per line in the format ImportTuples reads back with ImportJSONLines.
+//
+// Tuples are sorted by object, relation and user so that exports of an
+// unchanged store are byte-identical. The server does not return tuples in
+// that order, so they are sorted in runs of 100,000 keys; a store larger
+// than one run is spilled to temporary files in os.TempDir, readable only
+// by the current user and removed before ExportTuples returns, and the runs
+// are merged as the output is written. Memory use therefore stays flat
+// however large the store. It returns the number of tuples written.
+func (c *Client) ExportTuples(ctx context.Context, w io.Writer) (int, error) {
+	s := &tupleSorter{runSize: exportRunSize}
+	defer s.close()
+	var spillErr error
+	err := c.readPages(ctx, client.ClientReadRequest{}, func(page []openfga.Tuple, _ bool) bool {
+		for _, t := range page {
+			if spillErr = s.add(t.Key); spillErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err == nil {
+		err = spillErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("export: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	n := 0
+	err = s.each(func(k openfga.TupleKey) error {
+		if err := enc.Encode(k); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, fmt.Errorf("export: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("export: %w", err)
+	}
+	return n, nil
+}
+
+// tupleKeyLess orders tuple keys by object, relation and user.
+func tupleKeyLess(a, b openfga.TupleKey) bool {
+	if a.Object != b.Object {
+		return a.Object < b.Object
+	}
+	if a.Relation != b.Relation {
+		return a.Relation < b.Relation
+	}
+	return a.User < b.User
+}
+
+// tupleSorter sorts tuple keys in bounded memory: every runSize keys are
+// sorted and spilled to a temporary file, and the runs are merged when the
+// keys are read back.
+type tupleSorter struct {
+	runSize int
+	keys    []openfga.TupleKey
+	runs    []*os.File
+}
+
+func (s *tupleSorter) add(k openfga.TupleKey) error {
+	s.keys = append(s.keys, k)
+	if len(s.keys) < s.runSize {
+		return nil
+	}
+	return s.spill()
+}
+
+// spill writes the buffered keys, sorted, to a new run file.
+func (s *tupleSorter) spill() error {
+	s.sortKeys()
+	f, err := os.CreateTemp("", "fga-export-*.jsonl")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f)
+	bw := bufio.NewWriter(f)
+	enc := json.NewEncoder(bw)
+	for _, k := range s.keys {
+		if err := enc.Encode(k); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	s.keys = s.keys[:0]
+	return nil
+}
+
+func (s *tupleSorter) sortKeys() {
+	sort.Slice(s.keys, func(i, j int) bool { return tupleKeyLess(s.keys[i], s.keys[j]) })
+}
+
+// each calls fn with every key added, in order, stopping at the first error.
+func (s *tupleSorter) each(fn func(openfga.TupleKey) error) error {
+	s.sortKeys()
+	if len(s.runs) == 0 {
+		for _, k := range s.keys {
+			if err := fn(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Merge the run files and the keys still buffered.
+	runs := make([]*sortedRun, 0, len(s.runs)+1)
+	runs = append(runs, &sortedRun{mem: s.keys})
+	for _, f := range s.runs {
+		runs = append(runs, &sortedRun{dec: json.NewDecoder(bufio.NewReader(f))})
+	}
+	h := &runHeap{}
+	for _, r := range runs {
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.runs = append(h.runs, r)
+		}
+	}
+	heap.Init(h)
+	for h.Len() > 0 {
+		r := h.runs[0]
+		if err := fn(r.key); err != nil {
+			return err
+		}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return nil
+}
+
+// close removes the run files.
+func (s *tupleSorter) close() {
+	for _, f := range s.runs {
+		f.Close()
+		os.Remove(f.Name())
+	}
+}
+
+// sortedRun reads keys in order from a run file, or from memory when dec
+// is nil. key is the current key.
+type sortedRun struct {
+	key openfga.TupleKey
+	dec *json.Decoder
+	mem []openfga.TupleKey
+}
+
+// next advances to the following key, reporting false at the end.
+func (r *sortedRun) next() (bool, error) {
+	if r.dec == nil {
+		if len(r.mem) == 0 {
+			return false, nil
+		}
+		r.key, r.mem = r.mem[0], r.mem[1:]
+		return true, nil
+	}
+	r.key = openfga.TupleKey{}
+	switch err := r.dec.Decode(&r.key); err {
+	case nil:
+		return true, nil
+	case io.EOF:
+		return false, nil
+	default:
+		return false, fmt.Errorf("read sorted run: %w", err)
+	}
+}
+
+// runHeap orders runs by their current key.
+type runHeap struct{ runs []*sortedRun }
+
+func (h *runHeap) Len() int           { return len(h.runs) }
+func (h *runHeap) Less(i, j int) bool { return tupleKeyLess(h.runs[i].key, h.runs[j].key) }
+func (h *runHeap) Swap(i, j int)      { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+func (h *runHeap) Push(x interface{}) { h.runs = append(h.runs, x.(*sortedRun)) }
+func (h *runHeap) Pop() interface{} {
+	r := h.runs[len(h.runs)-1]
+	h.runs = h.runs[:len(h.runs)-1]
+	return r
+}
@@ -0,0 +1,74 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// readStub serves tuples from Read in pages of pageSize.
+type readStub struct {
+	FGA
+	tuples   []openfga.Tuple
+	pageSize int
+}
+
+func (s *readStub) Read(ctx context.Context, body client.ClientReadRequest, opts client.ClientReadOptions) (*client.ClientReadResponse, error) {
+	start := 0
+	if opts.ContinuationToken != nil {
+		fmt.Sscan(*opts.ContinuationToken, &start)
+	}
+	end := min(start+s.pageSize, len(s.tuples))
+	resp := &client.ClientReadResponse{Tuples: s.tuples[start:end]}
+	if end < len(s.tuples) {
+		resp.ContinuationToken = fmt.Sprint(end)
+	}
+	return resp, nil
+}
+
+func TestExportTuplesSorted(t *testing.T) {
+	var tuples []openfga.Tuple
+	var want []string
+	for doc := 0; doc < 5; doc++ {
+		for _, rel := range []string{"editor", "viewer"} {
+			for u := 0; u < 4; u++ {
+				k := openfga.TupleKey{User: fmt.Sprintf("user:%d", u), Relation: rel, Object: fmt.Sprintf("document:%d", doc)}
+				tuples = append(tuples, openfga.Tuple{Key: k})
+				want = append(want, fmt.Sprintf(`{"object":%q,"relation":%q,"user":%q}`, k.Object, k.Relation, k.User))
+			}
+		}
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(tuples), func(i, j int) { tuples[i], tuples[j] = tuples[j], tuples[i] })
+
+	for _, runSize := range []int{1000, 7, 1} {
+		t.Run(fmt.Sprintf("run size %d", runSize), func(t *testing.T) {
+			defer func(n int) { exportRunSize = n }(exportRunSize)
+			exportRunSize = runSize
+			tmp := t.TempDir()
+			t.Setenv("TMPDIR", tmp)
+
+			c := NewWithFGA(&readStub{tuples: tuples, pageSize: 6}, Config{StoreID: "01HVMMBCMGZNT3SED4Z17ECXCA"})
+			var out bytes.Buffer
+			n, err := c.ExportTuples(context.Background(), &out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(want) {
+				t.Errorf("exported %d tuples, want %d", n, len(want))
+			}
+			if got, want := out.String(), strings.Join(want, "\n")+"\n"; got != want {
+				t.Errorf("export =\n%swant\n%s", got, want)
+			}
+			if left, _ := os.ReadDir(tmp); len(left) != 0 {
+				t.Errorf("%d temporary files left behind", len(left))
+			}
+		})
+	}
+}
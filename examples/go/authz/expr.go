@@ -0,0 +1,133 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+)
+
+// Expr is a composite authorization requirement for CheckExpr, e.g.
+// "(editor and mfa) or owner":
+//
+//	Any(All(Rel("editor", doc), Rel("mfa_verified", session)), Rel("owner", doc))
+//
+// Build expressions with Rel, All, Any and Not.
+type Expr struct {
+	op       exprOp
+	relation string
+	object   string
+	children []Expr
+}
+
+type exprOp int
+
+const (
+	exprRel exprOp = iota
+	exprAll
+	exprAny
+	exprNot
+)
+
+// Rel requires the user to hold relation on object.
+func Rel(relation, object string) Expr {
+	return Expr{op: exprRel, relation: relation, object: object}
+}
+
+// All requires every expression to hold. All() holds.
+func All(exprs ...Expr) Expr {
+	return Expr{op: exprAll, children: exprs}
+}
+
+// Any requires at least one expression to hold. Any() does not hold.
+func Any(exprs ...Expr) Expr {
+	return Expr{op: exprAny, children: exprs}
+}
+
+// Not requires expr not to hold.
+func Not(expr Expr) Expr {
+	return Expr{op: exprNot, children: []Expr{expr}}
+}
+
+// CheckExpr evaluates expr for user, with each Rel answered by Check and
+// opts. The operands of All and Any are evaluated concurrently, with at
+// most Config.CheckConcurrency checks in flight, and evaluation stops as
+// soon as the outcome is known: the first operand that fails All or
+// satisfies Any cancels the checks still running. A failed check only fails
+// CheckExpr if the other operands do not decide the outcome without it. An
+// empty user defaults to the one attached to ctx by WithUser.
+func (c *Client) CheckExpr(ctx context.Context, user string, expr Expr, opts ...QueryOption) (bool, error) {
+	e := exprEval{c: c, user: userOrContext(ctx, user), opts: opts, sem: make(chan struct{}, c.checkConcurrency)}
+	allowed, err := e.eval(ctx, expr)
+	if err != nil {
+		return false, fmt.Errorf("check expr: %w", err)
+	}
+	return allowed, nil
+}
+
+type exprEval struct {
+	c    *Client
+	user string
+	opts []QueryOption
+	// sem bounds the checks in flight across the whole expression.
+	sem chan struct{}
+}
+
+func (e *exprEval) eval(ctx context.Context, x Expr) (bool, error) {
+	switch x.op {
+	case exprRel:
+		select {
+		case e.sem <- struct{}{}:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		defer func() { <-e.sem }()
+		return e.c.Check(ctx, e.user, x.relation, x.object, e.opts...)
+	case exprNot:
+		ok, err := e.eval(ctx, x.children[0])
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	}
+
+	// decisive is the operand result that settles All (false) or Any (true).
+	decisive := x.op == exprAny
+	switch len(x.children) {
+	case 0:
+		return !decisive, nil
+	case 1:
+		return e.eval(ctx, x.children[0])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		ok  bool
+		err error
+	}
+	// Buffered so that operands still running after an early return do not
+	// block; cancel stops them.
+	results := make(chan result, len(x.children))
+	for _, child := range x.children {
+		child := child
+		go func() {
+			ok, err := e.eval(ctx, child)
+			results <- result{ok, err}
+		}()
+	}
+	var firstErr error
+	for range x.children {
+		r := <-results
+		switch {
+		case r.err != nil:
+			if firstErr == nil {
+				firstErr = r.err
+			}
+		case r.ok == decisive:
+			return decisive, nil
+		}
+	}
+	if firstErr != nil {
+		return false, firstErr
+	}
+	return !decisive, nil
+}
@@ -0,0 +1,67 @@
+package authz_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	"github.com/bogdanticu88/openfga-examples/authz/testutil"
+	"github.com/openfga/go-sdk/client"
+)
+
+func TestCheckExpr(t *testing.T) {
+	fga := testutil.LoadFixture(t, `model
+  schema 1.1
+
+type user
+
+type doc
+  relations
+    define granted: [user]
+    define denied: [user]
+`, []client.ClientTupleKey{
+		{User: "user:anne", Relation: "granted", Object: "doc:1"},
+	})
+	yes := authz.Rel("granted", "doc:1")
+	no := authz.Rel("denied", "doc:1")
+	broken := authz.Rel("undefined", "doc:1")
+
+	tests := []struct {
+		name    string
+		expr    authz.Expr
+		want    bool
+		wantErr bool
+	}{
+		{"true", yes, true, false},
+		{"false", no, false, false},
+		{"not true", authz.Not(yes), false, false},
+		{"not false", authz.Not(no), true, false},
+		{"all of nothing", authz.All(), true, false},
+		{"any of nothing", authz.Any(), false, false},
+		{"true and true", authz.All(yes, yes), true, false},
+		{"true and false", authz.All(yes, no), false, false},
+		{"false and false", authz.All(no, no), false, false},
+		{"true or false", authz.Any(yes, no), true, false},
+		{"false or true", authz.Any(no, yes), true, false},
+		{"false or false", authz.Any(no, no), false, false},
+		{"nested", authz.Any(authz.All(yes, no), authz.Not(no)), true, false},
+		{"nested denied", authz.All(authz.Any(no, yes), authz.Not(authz.All(yes, yes))), false, false},
+		{"error", broken, false, true},
+		{"error decided by any", authz.Any(broken, yes), true, false},
+		{"error decided by all", authz.All(broken, no), false, false},
+		{"error undecided by all", authz.All(broken, yes), false, true},
+		{"error undecided by any", authz.Any(broken, no), false, true},
+		{"not error", authz.Not(broken), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := fga.CheckExpr(context.Background(), "user:anne", tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckExpr error = %v, want error %v", err, tt.wantErr)
+			}
+			if allowed != tt.want {
+				t.Errorf("CheckExpr = %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
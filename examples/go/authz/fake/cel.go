@@ -0,0 +1,248 @@
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	openfga "github.com/openfga/go-sdk"
+)
+
+// The fake evaluates conditions with cel-go, the library the server uses,
+// with the standard CEL library and the parameters declared with the types
+// the model gives them. The server's ipaddress type is not implemented: a
+// condition with an ipaddress parameter is reported as ErrUnsupported.
+
+// conditionMet evaluates the condition of t, if any, with the tuple's
+// stored context merged over the request context, as the server does.
+func (ev *evaluator) conditionMet(t openfga.TupleKey) (bool, error) {
+	if t.Condition == nil {
+		return true, nil
+	}
+	name := t.Condition.Name
+	tuple := key(t.User, t.Relation, t.Object)
+	cond, ok := ev.conditions[name]
+	if !ok {
+		return false, fmt.Errorf("fake: tuple %s: condition %q is not defined", tuple, name)
+	}
+	merged := map[string]interface{}{}
+	for k, v := range ev.context {
+		merged[k] = v
+	}
+	if t.Condition.Context != nil {
+		for k, v := range *t.Condition.Context {
+			merged[k] = v
+		}
+	}
+	// The server sees the context as JSON; so does the fake, which also
+	// gives Go values such as time.Time their wire form.
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return false, fmt.Errorf("fake: tuple %s: condition %q: %w", tuple, name, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var wire map[string]interface{}
+	if err := dec.Decode(&wire); err != nil {
+		return false, fmt.Errorf("fake: tuple %s: condition %q: %w", tuple, name, err)
+	}
+
+	env := map[string]interface{}{}
+	var params map[string]openfga.ConditionParamTypeRef
+	if cond.Parameters != nil {
+		params = *cond.Parameters
+	}
+	for param, typ := range params {
+		raw, ok := wire[param]
+		if !ok {
+			return false, fmt.Errorf("fake: tuple %s is missing context parameter %q of condition %q", tuple, param, name)
+		}
+		v, err := celParam(raw, typ)
+		if err != nil {
+			return false, fmt.Errorf("fake: tuple %s: condition %q: parameter %q: %w", tuple, name, param, err)
+		}
+		env[param] = v
+	}
+
+	met, err := celEval(cond.Expression, params, env)
+	if err != nil {
+		return false, fmt.Errorf("fake: tuple %s: condition %q: %w", tuple, name, err)
+	}
+	return met, nil
+}
+
+// celParam converts a JSON context value to the CEL value of a parameter
+// of type typ.
+func celParam(raw interface{}, typ openfga.ConditionParamTypeRef) (interface{}, error) {
+	mismatch := func() error { return fmt.Errorf("%v is not a valid %s", raw, typ.TypeName) }
+	switch typ.TypeName {
+	case openfga.TYPENAME_ANY:
+		return celJSON(raw), nil
+	case openfga.TYPENAME_BOOL:
+		if b, ok := raw.(bool); ok {
+			return b, nil
+		}
+	case openfga.TYPENAME_STRING:
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+	case openfga.TYPENAME_INT:
+		if n, ok := raw.(json.Number); ok {
+			if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	case openfga.TYPENAME_UINT:
+		if n, ok := raw.(json.Number); ok {
+			if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+				return u, nil
+			}
+		}
+	case openfga.TYPENAME_DOUBLE:
+		if n, ok := raw.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				return f, nil
+			}
+		}
+	case openfga.TYPENAME_TIMESTAMP:
+		if s, ok := raw.(string); ok {
+			if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return ts, nil
+			}
+		}
+	case openfga.TYPENAME_DURATION:
+		if s, ok := raw.(string); ok {
+			if d, err := time.ParseDuration(s); err == nil {
+				return d, nil
+			}
+		}
+	case openfga.TYPENAME_LIST:
+		elems, ok := raw.([]interface{})
+		if !ok || typ.GenericTypes == nil || len(*typ.GenericTypes) != 1 {
+			return nil, mismatch()
+		}
+		out := make([]interface{}, len(elems))
+		for i, e := range elems {
+			v, err := celParam(e, (*typ.GenericTypes)[0])
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case openfga.TYPENAME_MAP:
+		m, ok := raw.(map[string]interface{})
+		if !ok || typ.GenericTypes == nil || len(*typ.GenericTypes) != 1 {
+			return nil, mismatch()
+		}
+		out := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			v, err := celParam(e, (*typ.GenericTypes)[0])
+			if err != nil {
+				return nil, err
+			}
+			out[k] = v
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("type %s: %w", typ.TypeName, ErrUnsupported)
+	}
+	return nil, mismatch()
+}
+
+// celJSON converts an untyped JSON value as CEL does: numbers are doubles.
+func celJSON(raw interface{}) interface{} {
+	switch v := raw.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = celJSON(e)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = celJSON(e)
+		}
+		return out
+	}
+	return raw
+}
+
+// celType returns the CEL type of a parameter of type typ.
+func celType(typ openfga.ConditionParamTypeRef) (*cel.Type, error) {
+	switch typ.TypeName {
+	case openfga.TYPENAME_ANY:
+		return cel.DynType, nil
+	case openfga.TYPENAME_BOOL:
+		return cel.BoolType, nil
+	case openfga.TYPENAME_STRING:
+		return cel.StringType, nil
+	case openfga.TYPENAME_INT:
+		return cel.IntType, nil
+	case openfga.TYPENAME_UINT:
+		return cel.UintType, nil
+	case openfga.TYPENAME_DOUBLE:
+		return cel.DoubleType, nil
+	case openfga.TYPENAME_TIMESTAMP:
+		return cel.TimestampType, nil
+	case openfga.TYPENAME_DURATION:
+		return cel.DurationType, nil
+	case openfga.TYPENAME_LIST, openfga.TYPENAME_MAP:
+		if typ.GenericTypes == nil || len(*typ.GenericTypes) != 1 {
+			return nil, fmt.Errorf("type %s needs one generic type", typ.TypeName)
+		}
+		elem, err := celType((*typ.GenericTypes)[0])
+		if err != nil {
+			return nil, err
+		}
+		if typ.TypeName == openfga.TYPENAME_LIST {
+			return cel.ListType(elem), nil
+		}
+		return cel.MapType(cel.StringType, elem), nil
+	}
+	return nil, fmt.Errorf("type %s: %w", typ.TypeName, ErrUnsupported)
+}
+
+// celEval compiles expr, which must yield a bool, with params declared,
+// and evaluates it with the parameter values in vars.
+func celEval(expr string, params map[string]openfga.ConditionParamTypeRef, vars map[string]interface{}) (bool, error) {
+	opts := make([]cel.EnvOption, 0, len(params))
+	for name, typ := range params {
+		t, err := celType(typ)
+		if err != nil {
+			return false, fmt.Errorf("parameter %q: %w", name, err)
+		}
+		opts = append(opts, cel.Variable(name, t))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return false, err
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return false, iss.Err()
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return false, fmt.Errorf("expression yields %s, not bool", ast.OutputType())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression yields %T, not bool", out.Value())
+	}
+	return b, nil
+}
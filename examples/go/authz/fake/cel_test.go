@@ -0,0 +1,113 @@
+package fake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// celParams declares the parameters of the celEval tests.
+var celParams = func() map[string]openfga.ConditionParamTypeRef {
+	typ := func(name openfga.TypeName, generic ...openfga.ConditionParamTypeRef) openfga.ConditionParamTypeRef {
+		ref := openfga.ConditionParamTypeRef{TypeName: name}
+		if generic != nil {
+			ref.GenericTypes = &generic
+		}
+		return ref
+	}
+	return map[string]openfga.ConditionParamTypeRef{
+		"now":   typ(openfga.TYPENAME_TIMESTAMP),
+		"until": typ(openfga.TYPENAME_TIMESTAMP),
+		"ttl":   typ(openfga.TYPENAME_DURATION),
+		"n":     typ(openfga.TYPENAME_INT),
+		"u":     typ(openfga.TYPENAME_UINT),
+		"ratio": typ(openfga.TYPENAME_DOUBLE),
+		"dept":  typ(openfga.TYPENAME_STRING),
+		"depts": typ(openfga.TYPENAME_LIST, typ(openfga.TYPENAME_STRING)),
+		"tags":  typ(openfga.TYPENAME_MAP, typ(openfga.TYPENAME_BOOL)),
+		"attrs": typ(openfga.TYPENAME_ANY),
+		"on":    typ(openfga.TYPENAME_BOOL),
+	}
+}()
+
+func TestCELEval(t *testing.T) {
+	noon := time.Date(2026, 10, 14, 12, 30, 0, 0, time.UTC)
+	vars := map[string]interface{}{
+		"now":   noon,
+		"until": noon.Add(time.Hour),
+		"ttl":   30 * time.Minute,
+		"n":     int64(3),
+		"u":     uint64(7),
+		"ratio": 0.5,
+		"dept":  "eng",
+		"depts": []interface{}{"eng", "ops"},
+		"tags":  map[string]interface{}{"beta": true},
+		"attrs": map[string]interface{}{"level": 2.0},
+		"on":    true,
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"now < until", true},
+		{"now + ttl < until", true},
+		{"now + duration('2h') < until", false},
+		{"until - now == duration(\"1h\")", true},
+		{"now == timestamp('2026-10-14T12:30:00Z')", true},
+		{"now.getHours() >= 9 && now.getHours() <= 17", true},
+		{"now.getDayOfWeek() == 3", true},
+		{"n * 2 == 6 && n % 2 == 1", true},
+		{"u > 5u", true},
+		{"ratio < 1.0", true},
+		{"dept in depts && !('sales' in depts)", true},
+		{"'beta' in tags && tags['beta']", true},
+		{"depts.exists(d, d.startsWith('o'))", true},
+		{"depts.all(d, size(d) == 3)", true},
+		{"dept.matches('^e')", true},
+		{"attrs.level >= 2.0", true},
+		{"on && n in [1, 2, 3]", true},
+	}
+	for _, tt := range tests {
+		got, err := celEval(tt.expr, celParams, vars)
+		if err != nil {
+			t.Errorf("celEval(%q): %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("celEval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCELEvalErrors(t *testing.T) {
+	vars := map[string]interface{}{"n": int64(3), "dept": "eng", "depts": []interface{}{}}
+	tests := []struct {
+		expr        string
+		params      map[string]openfga.ConditionParamTypeRef
+		unsupported bool
+	}{
+		{expr: "n", params: celParams},
+		{expr: "missing == 1", params: celParams},
+		{expr: "n < dept", params: celParams},
+		{expr: "n == (1", params: celParams},
+		{expr: "n / 0 == 1", params: celParams},
+		{expr: "depts[0] == 'eng'", params: celParams},
+		{
+			expr:        "ip.in_cidr('10.0.0.0/8')",
+			params:      map[string]openfga.ConditionParamTypeRef{"ip": {TypeName: openfga.TYPENAME_IPADDRESS}},
+			unsupported: true,
+		},
+	}
+	for _, tt := range tests {
+		_, err := celEval(tt.expr, tt.params, vars)
+		if err == nil {
+			t.Errorf("celEval(%q) succeeded, want error", tt.expr)
+			continue
+		}
+		if got := errors.Is(err, ErrUnsupported); got != tt.unsupported {
+			t.Errorf("celEval(%q) = %v; ErrUnsupported %v, want %v", tt.expr, err, got, tt.unsupported)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// evaluator resolves checks against one model and a snapshot of tuples.
+type evaluator struct {
+	types      map[string]openfga.TypeDefinition
+	conditions map[string]openfga.Condition
+	tuples     []openfga.TupleKey
+	// context is the request context conditions are evaluated with.
+	context map[string]interface{}
+}
+
+// evaluator must be called with f.mu held.
+func (f *Client) evaluator(storeID, modelID *string, contextual []client.ClientContextualTupleKey, reqContext *map[string]interface{}) (*evaluator, error) {
+	st, err := f.store(storeID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := st.model(modelID)
+	if err != nil {
+		return nil, err
+	}
+	ev := &evaluator{types: make(map[string]openfga.TypeDefinition, len(m.TypeDefinitions))}
+	for _, td := range m.TypeDefinitions {
+		ev.types[td.Type] = td
+	}
+	if m.Conditions != nil {
+		ev.conditions = *m.Conditions
+	}
+	if reqContext != nil {
+		ev.context = *reqContext
+	}
+	for _, t := range st.tuples {
+		ev.tuples = append(ev.tuples, t.Key)
+	}
+	ev.tuples = append(ev.tuples, contextual...)
+	return ev, nil
+}
+
+// candidates returns the distinct "type:id" values of the given type that
+// fields extracts from any tuple, sorted. Userset suffixes are dropped.
+func (ev *evaluator) candidates(fields func(openfga.TupleKey) []string, typ string) []string {
+	seen := map[string]bool{}
+	for _, t := range ev.tuples {
+		for _, v := range fields(t) {
+			v, _, _ = strings.Cut(v, "#")
+			if strings.HasPrefix(v, typ+":") {
+				seen[v] = true
+			}
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for v := range seen {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (ev *evaluator) check(user, relation, object string, depth int) (bool, error) {
+	if depth > maxDepth {
+		return false, fmt.Errorf("fake: resolution depth exceeded checking %s#%s", object, relation)
+	}
+	if user == object+"#"+relation {
+		return true, nil
+	}
+	typ, _, ok := strings.Cut(object, ":")
+	if !ok {
+		return false, fmt.Errorf("fake: invalid object %q", object)
+	}
+	td, ok := ev.types[typ]
+	if !ok {
+		return false, fmt.Errorf("fake: type %q is not defined", typ)
+	}
+	if td.Relations == nil {
+		return false, fmt.Errorf("fake: relation %s#%s is not defined", typ, relation)
+	}
+	rw, ok := (*td.Relations)[relation]
+	if !ok {
+		return false, fmt.Errorf("fake: relation %s#%s is not defined", typ, relation)
+	}
+	return ev.eval(rw, user, relation, object, depth)
+}
+
+func (ev *evaluator) eval(us openfga.Userset, user, relation, object string, depth int) (bool, error) {
+	switch {
+	case us.This != nil:
+		return ev.direct(user, relation, object, depth)
+	case us.ComputedUserset != nil:
+		return ev.check(user, us.ComputedUserset.GetRelation(), object, depth+1)
+	case us.TupleToUserset != nil:
+		tupleset := us.TupleToUserset.Tupleset.GetRelation()
+		computed := us.TupleToUserset.ComputedUserset.GetRelation()
+		for _, t := range ev.tuples {
+			if t.Object != object || t.Relation != tupleset {
+				continue
+			}
+			if !ev.hasRelation(t.User, computed) {
+				continue
+			}
+			met, err := ev.conditionMet(t)
+			if err != nil {
+				return false, err
+			}
+			if !met {
+				continue
+			}
+			ok, err := ev.check(user, computed, t.User, depth+1)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case us.Union != nil:
+		for _, child := range us.Union.Child {
+			ok, err := ev.eval(child, user, relation, object, depth)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case us.Intersection != nil:
+		for _, child := range us.Intersection.Child {
+			ok, err := ev.eval(child, user, relation, object, depth)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case us.Difference != nil:
+		ok, err := ev.eval(us.Difference.Base, user, relation, object, depth)
+		if err != nil || !ok {
+			return false, err
+		}
+		excluded, err := ev.eval(us.Difference.Subtract, user, relation, object, depth)
+		return !excluded, err
+	}
+	return false, fmt.Errorf("fake: empty rewrite for %s#%s", object, relation)
+}
+
+// direct resolves tuples assigned to object#relation: the user itself, a
+// wildcard of the user's type, or a userset the user belongs to.
+func (ev *evaluator) direct(user, relation, object string, depth int) (bool, error) {
+	userType, _, _ := strings.Cut(user, ":")
+	wildcard := userType + ":*"
+	for _, t := range ev.tuples {
+		if t.Object != object || t.Relation != relation {
+			continue
+		}
+		met, err := ev.conditionMet(t)
+		if err != nil {
+			return false, err
+		}
+		if !met {
+			continue
+		}
+		switch {
+		case t.User == user:
+			return true, nil
+		case t.User == wildcard && !strings.Contains(user, "#"):
+			return true, nil
+		case strings.Contains(t.User, "#"):
+			setObject, setRelation, _ := strings.Cut(t.User, "#")
+			ok, err := ev.check(user, setRelation, setObject, depth+1)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+	}
+	return false, nil
+}
+
+func (ev *evaluator) hasRelation(object, relation string) bool {
+	typ, _, _ := strings.Cut(object, ":")
+	td, ok := ev.types[typ]
+	if !ok || td.Relations == nil {
+		return false
+	}
+	_, ok = (*td.Relations)[relation]
+	return ok
+}
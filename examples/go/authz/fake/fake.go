@@ -0,0 +1,459 @@
+// Package fake provides an in-memory implementation of authz.FGA for tests
+// that should not depend on a running OpenFGA server:
+//
+//	fga := authz.NewWithFGA(fake.New(), authz.Config{})
+//
+// Checks are evaluated against the written tuples and model. Direct
+// assignment (including wildcards and usersets), computed usersets,
+// tuple-to-userset, union, intersection and exclusion are supported, and
+// so are conditional tuples, evaluated with the request context by cel-go
+// as the server does. Conditions on ipaddress parameters, and Expand, fail
+// with ErrUnsupported. Pagination is not simulated: every listing is
+// returned in a single page.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// maxDepth mirrors the server's default resolution depth limit.
+const maxDepth = 25
+
+var (
+	// ErrNotFound is returned for unknown stores and models.
+	ErrNotFound = errors.New("fake: not found")
+	// ErrUnsupported is returned for features the fake does not evaluate.
+	ErrUnsupported = errors.New("fake: unsupported")
+)
+
+// Client is an in-memory OpenFGA. The zero value is not usable; call New.
+// It is safe for concurrent use.
+type Client struct {
+	mu     sync.Mutex
+	seq    int
+	stores map[string]*store
+}
+
+type store struct {
+	info       openfga.Store
+	models     []openfga.AuthorizationModel
+	tuples     []openfga.Tuple
+	changes    []openfga.TupleChange
+	assertions map[string][]openfga.Assertion
+}
+
+// New returns an empty fake with no stores.
+func New() *Client {
+	return &Client{stores: map[string]*store{}}
+}
+
+// nextID returns a fixed-width, monotonically increasing ID so that IDs
+// sort by creation time like the server's ULIDs.
+func (f *Client) nextID() string {
+	f.seq++
+	return fmt.Sprintf("%026d", f.seq)
+}
+
+func (f *Client) store(id *string) (*store, error) {
+	if id == nil || *id == "" {
+		return nil, fmt.Errorf("fake: store id required")
+	}
+	st, ok := f.stores[*id]
+	if !ok {
+		return nil, &notFound{msg: "store " + *id, kind: authz.ErrStoreNotFound}
+	}
+	return st, nil
+}
+
+func (st *store) model(id *string) (*openfga.AuthorizationModel, error) {
+	if id == nil || *id == "" {
+		if len(st.models) == 0 {
+			return nil, &notFound{msg: "store " + st.info.Id + " has no authorization model", kind: authz.ErrModelNotFound}
+		}
+		return &st.models[len(st.models)-1], nil
+	}
+	for i := range st.models {
+		if st.models[i].Id == *id {
+			return &st.models[i], nil
+		}
+	}
+	return nil, &notFound{msg: "authorization model " + *id, kind: authz.ErrModelNotFound}
+}
+
+// notFound is an ErrNotFound for a store or model that also matches the
+// corresponding authz error, as the server's responses do.
+type notFound struct {
+	msg  string
+	kind error
+}
+
+func (e *notFound) Error() string { return e.msg + ": " + ErrNotFound.Error() }
+
+func (e *notFound) Is(target error) bool { return target == ErrNotFound || target == e.kind }
+
+func (f *Client) CreateStore(ctx context.Context, body client.ClientCreateStoreRequest) (*client.ClientCreateStoreResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now().UTC()
+	info := openfga.Store{Id: f.nextID(), Name: body.Name, CreatedAt: now, UpdatedAt: now}
+	f.stores[info.Id] = &store{info: info}
+	return &client.ClientCreateStoreResponse{Id: info.Id, Name: info.Name, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (f *Client) ListStores(ctx context.Context, opts client.ClientListStoresOptions) (*client.ClientListStoresResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stores := make([]openfga.Store, 0, len(f.stores))
+	for _, st := range f.stores {
+		stores = append(stores, st.info)
+	}
+	sort.Slice(stores, func(i, j int) bool { return stores[i].Id < stores[j].Id })
+	return &client.ClientListStoresResponse{Stores: stores}, nil
+}
+
+func (f *Client) DeleteStore(ctx context.Context, opts client.ClientDeleteStoreOptions) (*client.ClientDeleteStoreResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.store(opts.StoreId); err != nil {
+		return nil, err
+	}
+	delete(f.stores, *opts.StoreId)
+	return &client.ClientDeleteStoreResponse{}, nil
+}
+
+func (f *Client) WriteAuthorizationModel(ctx context.Context, body client.ClientWriteAuthorizationModelRequest, opts client.ClientWriteAuthorizationModelOptions) (*client.ClientWriteAuthorizationModelResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	m := openfga.AuthorizationModel{
+		Id:              f.nextID(),
+		SchemaVersion:   body.SchemaVersion,
+		TypeDefinitions: body.TypeDefinitions,
+		Conditions:      body.Conditions,
+	}
+	st.models = append(st.models, m)
+	return &client.ClientWriteAuthorizationModelResponse{AuthorizationModelId: m.Id}, nil
+}
+
+func (f *Client) ReadAuthorizationModel(ctx context.Context, opts client.ClientReadAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	if opts.AuthorizationModelId == nil || *opts.AuthorizationModelId == "" {
+		return nil, fmt.Errorf("fake: authorization model id required")
+	}
+	m, err := st.model(opts.AuthorizationModelId)
+	if err != nil {
+		return nil, err
+	}
+	return &client.ClientReadAuthorizationModelResponse{AuthorizationModel: m}, nil
+}
+
+// ReadLatestAuthorizationModel returns an empty response, not an error, for
+// a store without models, as the server does.
+func (f *Client) ReadLatestAuthorizationModel(ctx context.Context, opts client.ClientReadLatestAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &client.ClientReadAuthorizationModelResponse{}
+	if len(st.models) > 0 {
+		m := st.models[len(st.models)-1]
+		resp.AuthorizationModel = &m
+	}
+	return resp, nil
+}
+
+// ReadAuthorizationModels returns every model of the store in one page,
+// newest first, as the server orders them.
+func (f *Client) ReadAuthorizationModels(ctx context.Context, opts client.ClientReadAuthorizationModelsOptions) (*client.ClientReadAuthorizationModelsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]openfga.AuthorizationModel, len(st.models))
+	for i, m := range st.models {
+		models[len(models)-1-i] = m
+	}
+	return &client.ClientReadAuthorizationModelsResponse{AuthorizationModels: models}, nil
+}
+
+// Write applies the request atomically: writing a tuple that exists or
+// deleting one that does not fails the whole request.
+func (f *Client) Write(ctx context.Context, body client.ClientWriteRequest, opts client.ClientWriteOptions) (*client.ClientWriteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]bool, len(st.tuples))
+	for _, t := range st.tuples {
+		stored[key(t.Key.User, t.Key.Relation, t.Key.Object)] = true
+	}
+	for _, tk := range body.Deletes {
+		k := key(tk.User, tk.Relation, tk.Object)
+		if !stored[k] {
+			return nil, fmt.Errorf("fake: cannot delete tuple %s: %w", k, ErrNotFound)
+		}
+		delete(stored, k)
+	}
+	for _, tk := range body.Writes {
+		k := key(tk.User, tk.Relation, tk.Object)
+		if stored[k] {
+			return nil, fmt.Errorf("fake: cannot write tuple %s: it already exists", k)
+		}
+		stored[k] = true
+	}
+
+	now := time.Now().UTC()
+	resp := &client.ClientWriteResponse{}
+	for _, tk := range body.Deletes {
+		k := key(tk.User, tk.Relation, tk.Object)
+		for i, t := range st.tuples {
+			if key(t.Key.User, t.Key.Relation, t.Key.Object) == k {
+				st.tuples = append(st.tuples[:i], st.tuples[i+1:]...)
+				break
+			}
+		}
+		st.changes = append(st.changes, openfga.TupleChange{
+			TupleKey:  openfga.TupleKey{User: tk.User, Relation: tk.Relation, Object: tk.Object},
+			Operation: openfga.TUPLEOPERATION_DELETE,
+			Timestamp: now,
+		})
+		resp.Deletes = append(resp.Deletes, client.ClientWriteRequestDeleteResponse{TupleKey: tk, Status: client.SUCCESS})
+	}
+	for _, tk := range body.Writes {
+		st.tuples = append(st.tuples, openfga.Tuple{Key: tk, Timestamp: now})
+		st.changes = append(st.changes, openfga.TupleChange{
+			TupleKey:  tk,
+			Operation: openfga.TUPLEOPERATION_WRITE,
+			Timestamp: now,
+		})
+		resp.Writes = append(resp.Writes, client.ClientWriteRequestWriteResponse{TupleKey: tk, Status: client.SUCCESS})
+	}
+	return resp, nil
+}
+
+// Read returns the stored tuples matching every field set in body. As on
+// the server, an object of the form "type:" matches all objects of a type.
+func (f *Client) Read(ctx context.Context, body client.ClientReadRequest, opts client.ClientReadOptions) (*client.ClientReadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	tuples := []openfga.Tuple{}
+	for _, t := range st.tuples {
+		switch {
+		case body.User != nil && *body.User != "" && t.Key.User != *body.User:
+		case body.Relation != nil && *body.Relation != "" && t.Key.Relation != *body.Relation:
+		case body.Object != nil && *body.Object != "" && !objectMatches(t.Key.Object, *body.Object):
+		default:
+			tuples = append(tuples, t)
+		}
+	}
+	return &client.ClientReadResponse{Tuples: tuples}, nil
+}
+
+func objectMatches(object, filter string) bool {
+	if strings.HasSuffix(filter, ":") {
+		return strings.HasPrefix(object, filter)
+	}
+	return object == filter
+}
+
+// ReadChanges returns the changes after the continuation token. The token
+// is the number of changes seen so far, so it stays valid across writes.
+func (f *Client) ReadChanges(ctx context.Context, body client.ClientReadChangesRequest, opts client.ClientReadChangesOptions) (*client.ClientReadChangesResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	if opts.ContinuationToken != nil && *opts.ContinuationToken != "" {
+		start, err = strconv.Atoi(*opts.ContinuationToken)
+		if err != nil || start < 0 || start > len(st.changes) {
+			return nil, fmt.Errorf("fake: invalid continuation token %q", *opts.ContinuationToken)
+		}
+	}
+	changes := []openfga.TupleChange{}
+	for _, ch := range st.changes[start:] {
+		if body.Type == "" || strings.HasPrefix(ch.TupleKey.Object, body.Type+":") {
+			changes = append(changes, ch)
+		}
+	}
+	token := strconv.Itoa(len(st.changes))
+	return &client.ClientReadChangesResponse{Changes: changes, ContinuationToken: &token}, nil
+}
+
+func (f *Client) Check(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ev, err := f.evaluator(opts.StoreId, opts.AuthorizationModelId, body.ContextualTuples, body.Context)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := ev.check(body.User, body.Relation, body.Object, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed}}, nil
+}
+
+// ServerBatchCheck implements authz.BatchChecker. As on the server, an
+// unknown store or model fails the request and a check that cannot be
+// evaluated fails on its own.
+func (f *Client) ServerBatchCheck(ctx context.Context, checks []client.ClientCheckRequest, opts client.ClientCheckOptions) ([]authz.BatchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.evaluator(opts.StoreId, opts.AuthorizationModelId, nil, nil); err != nil {
+		return nil, err
+	}
+	results := make([]authz.BatchResult, len(checks))
+	for i, body := range checks {
+		ev, err := f.evaluator(opts.StoreId, opts.AuthorizationModelId, body.ContextualTuples, body.Context)
+		if err == nil {
+			results[i].Allowed, err = ev.check(body.User, body.Relation, body.Object, 0)
+		}
+		if err != nil {
+			results[i] = authz.BatchResult{Err: err}
+		}
+	}
+	return results, nil
+}
+
+// Expand is not supported.
+func (f *Client) Expand(ctx context.Context, body client.ClientExpandRequest, opts client.ClientExpandOptions) (*client.ClientExpandResponse, error) {
+	return nil, fmt.Errorf("expand: %w", ErrUnsupported)
+}
+
+// ListObjects checks every object of the requested type that appears in a
+// stored or contextual tuple.
+func (f *Client) ListObjects(ctx context.Context, body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions) (*client.ClientListObjectsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ev, err := f.evaluator(opts.StoreId, opts.AuthorizationModelId, body.ContextualTuples, body.Context)
+	if err != nil {
+		return nil, err
+	}
+	objects := []string{}
+	for _, obj := range ev.candidates(func(t openfga.TupleKey) []string { return []string{t.Object} }, body.Type) {
+		ok, err := ev.check(body.User, body.Relation, obj, 0)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			objects = append(objects, obj)
+		}
+	}
+	return &client.ClientListObjectsResponse{Objects: objects}, nil
+}
+
+// ListUsers checks every user of the filtered types that appears in a
+// stored or contextual tuple. Userset filters ("group#member") are not
+// supported.
+func (f *Client) ListUsers(ctx context.Context, body client.ClientListUsersRequest, opts client.ClientListUsersOptions) (*client.ClientListUsersResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ev, err := f.evaluator(opts.StoreId, opts.AuthorizationModelId, body.ContextualTuples, body.Context)
+	if err != nil {
+		return nil, err
+	}
+	object := body.Object.Type + ":" + body.Object.Id
+	users := []openfga.User{}
+	for _, filter := range body.UserFilters {
+		if filter.Relation != nil && *filter.Relation != "" {
+			return nil, fmt.Errorf("list users with userset filter %s#%s: %w", filter.Type, *filter.Relation, ErrUnsupported)
+		}
+		for _, u := range ev.candidates(func(t openfga.TupleKey) []string { return []string{t.User, t.Object} }, filter.Type) {
+			ok, err := ev.check(u, body.Relation, object, 0)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			typ, id, _ := strings.Cut(u, ":")
+			if id == "*" {
+				users = append(users, openfga.User{Wildcard: &openfga.TypedWildcard{Type: typ}})
+			} else {
+				users = append(users, openfga.User{Object: &openfga.FgaObject{Type: typ, Id: id}})
+			}
+		}
+	}
+	return &client.ClientListUsersResponse{Users: users}, nil
+}
+
+// WriteAssertions replaces the assertions of a model that exists in the
+// store.
+func (f *Client) WriteAssertions(ctx context.Context, body client.ClientWriteAssertionsRequest, opts client.ClientWriteAssertionsOptions) (*client.ClientWriteAssertionsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	m, err := st.model(opts.AuthorizationModelId)
+	if err != nil {
+		return nil, err
+	}
+	assertions := make([]openfga.Assertion, len(body))
+	for i, a := range body {
+		assertions[i] = a.ToAssertion()
+	}
+	if st.assertions == nil {
+		st.assertions = map[string][]openfga.Assertion{}
+	}
+	st.assertions[m.Id] = assertions
+	return &client.ClientWriteAssertionsResponse{}, nil
+}
+
+func (f *Client) ReadAssertions(ctx context.Context, opts client.ClientReadAssertionsOptions) (*client.ClientReadAssertionsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, err := f.store(opts.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	m, err := st.model(opts.AuthorizationModelId)
+	if err != nil {
+		return nil, err
+	}
+	assertions := append([]openfga.Assertion(nil), st.assertions[m.Id]...)
+	return &client.ClientReadAssertionsResponse{AuthorizationModelId: m.Id, Assertions: &assertions}, nil
+}
+
+func key(user, relation, object string) string {
+	return object + "#" + relation + "@" + user
+}
+
+var (
+	_ authz.FGA          = (*Client)(nil)
+	_ authz.BatchChecker = (*Client)(nil)
+)
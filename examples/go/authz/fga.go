@@ -0,0 +1,110 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+// FGA is the subset of the OpenFGA API the Client uses. New backs it with
+// the SDK's HTTP client; NewWithFGA accepts any implementation, such as the
+// in-memory fake in package authz/fake for offline tests.
+//
+// Methods take the SDK's request bodies and options and return its response
+// types, so an implementation sees exactly what would go over the wire.
+// An implementation holding connections may also implement io.Closer, which
+// Client.Close calls.
+type FGA interface {
+	CreateStore(ctx context.Context, body client.ClientCreateStoreRequest) (*client.ClientCreateStoreResponse, error)
+	ListStores(ctx context.Context, opts client.ClientListStoresOptions) (*client.ClientListStoresResponse, error)
+	DeleteStore(ctx context.Context, opts client.ClientDeleteStoreOptions) (*client.ClientDeleteStoreResponse, error)
+
+	WriteAuthorizationModel(ctx context.Context, body client.ClientWriteAuthorizationModelRequest, opts client.ClientWriteAuthorizationModelOptions) (*client.ClientWriteAuthorizationModelResponse, error)
+	ReadAuthorizationModel(ctx context.Context, opts client.ClientReadAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error)
+	ReadLatestAuthorizationModel(ctx context.Context, opts client.ClientReadLatestAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error)
+
+	Write(ctx context.Context, body client.ClientWriteRequest, opts client.ClientWriteOptions) (*client.ClientWriteResponse, error)
+	Read(ctx context.Context, body client.ClientReadRequest, opts client.ClientReadOptions) (*client.ClientReadResponse, error)
+	ReadChanges(ctx context.Context, body client.ClientReadChangesRequest, opts client.ClientReadChangesOptions) (*client.ClientReadChangesResponse, error)
+
+	Check(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error)
+	Expand(ctx context.Context, body client.ClientExpandRequest, opts client.ClientExpandOptions) (*client.ClientExpandResponse, error)
+	ListObjects(ctx context.Context, body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions) (*client.ClientListObjectsResponse, error)
+	ListUsers(ctx context.Context, body client.ClientListUsersRequest, opts client.ClientListUsersOptions) (*client.ClientListUsersResponse, error)
+	WriteAssertions(ctx context.Context, body client.ClientWriteAssertionsRequest, opts client.ClientWriteAssertionsOptions) (*client.ClientWriteAssertionsResponse, error)
+	ReadAssertions(ctx context.Context, opts client.ClientReadAssertionsOptions) (*client.ClientReadAssertionsResponse, error)
+}
+
+// sdkFGA adapts the SDK's fluent request builders to FGA.
+type sdkFGA struct {
+	c    *client.OpenFgaClient
+	http *http.Client
+	url  string
+}
+
+// Close drops the idle connections of the HTTP pool.
+func (s sdkFGA) Close() error {
+	s.http.CloseIdleConnections()
+	return nil
+}
+
+func (s sdkFGA) CreateStore(ctx context.Context, body client.ClientCreateStoreRequest) (*client.ClientCreateStoreResponse, error) {
+	return s.c.CreateStore(ctx).Body(body).Execute()
+}
+
+func (s sdkFGA) ListStores(ctx context.Context, opts client.ClientListStoresOptions) (*client.ClientListStoresResponse, error) {
+	return s.c.ListStores(ctx).Options(opts).Execute()
+}
+
+func (s sdkFGA) DeleteStore(ctx context.Context, opts client.ClientDeleteStoreOptions) (*client.ClientDeleteStoreResponse, error) {
+	return s.c.DeleteStore(ctx).Options(opts).Execute()
+}
+
+func (s sdkFGA) WriteAuthorizationModel(ctx context.Context, body client.ClientWriteAuthorizationModelRequest, opts client.ClientWriteAuthorizationModelOptions) (*client.ClientWriteAuthorizationModelResponse, error) {
+	return s.c.WriteAuthorizationModel(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) ReadAuthorizationModel(ctx context.Context, opts client.ClientReadAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error) {
+	return s.c.ReadAuthorizationModel(ctx).Options(opts).Execute()
+}
+
+func (s sdkFGA) ReadLatestAuthorizationModel(ctx context.Context, opts client.ClientReadLatestAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error) {
+	return s.c.ReadLatestAuthorizationModel(ctx).Options(opts).Execute()
+}
+
+func (s sdkFGA) Write(ctx context.Context, body client.ClientWriteRequest, opts client.ClientWriteOptions) (*client.ClientWriteResponse, error) {
+	return s.c.Write(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) Read(ctx context.Context, body client.ClientReadRequest, opts client.ClientReadOptions) (*client.ClientReadResponse, error) {
+	return s.c.Read(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) ReadChanges(ctx context.Context, body client.ClientReadChangesRequest, opts client.ClientReadChangesOptions) (*client.ClientReadChangesResponse, error) {
+	return s.c.ReadChanges(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) Check(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error) {
+	return s.c.Check(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) Expand(ctx context.Context, body client.ClientExpandRequest, opts client.ClientExpandOptions) (*client.ClientExpandResponse, error) {
+	return s.c.Expand(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) ListObjects(ctx context.Context, body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions) (*client.ClientListObjectsResponse, error) {
+	return s.c.ListObjects(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) ListUsers(ctx context.Context, body client.ClientListUsersRequest, opts client.ClientListUsersOptions) (*client.ClientListUsersResponse, error) {
+	return s.c.ListUsers(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) WriteAssertions(ctx context.Context, body client.ClientWriteAssertionsRequest, opts client.ClientWriteAssertionsOptions) (*client.ClientWriteAssertionsResponse, error) {
+	return s.c.WriteAssertions(ctx).Body(body).Options(opts).Execute()
+}
+
+func (s sdkFGA) ReadAssertions(ctx context.Context, opts client.ClientReadAssertionsOptions) (*client.ClientReadAssertionsResponse, error) {
+	return s.c.ReadAssertions(ctx).Options(opts).Execute()
+}
@@ -0,0 +1,94 @@
+package authz
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// GenerateConstants emits Go source declaring a constant for every type and
+// relation in typeDefs, so that callers write authz.Check(ctx, u,
+// model.DocumentViewer, ...) instead of a bare, typo-prone string. Types
+// become Type<Name>; relations become <Type><Relation>, grouped by type.
+// Hyphens and underscores start a new word: "github-repo" yields
+// TypeGithubRepo.
+//
+// Regenerate after each model change with a go:generate directive next to
+// a small program that reads the model and writes the file:
+//
+//	//go:generate go run ./internal/genconsts -model ../model.fga -pkg model -out consts_gen.go
+//
+// where genconsts calls ParseDSL and GenerateConstants.
+func GenerateConstants(typeDefs []openfga.TypeDefinition, pkg string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("generate constants: invalid package name %q", pkg)
+	}
+	tds := append([]openfga.TypeDefinition(nil), typeDefs...)
+	sort.Slice(tds, func(i, j int) bool { return tds[i].Type < tds[j].Type })
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by authz.GenerateConstants. DO NOT EDIT.\n\npackage %s\n", pkg)
+
+	declared := map[string]string{}
+	declare := func(ident, what string) error {
+		if prev, dup := declared[ident]; dup {
+			return fmt.Errorf("generate constants: %s and %s both map to %s", prev, what, ident)
+		}
+		declared[ident] = what
+		return nil
+	}
+
+	b.WriteString("\n// Types.\nconst (\n")
+	for _, td := range tds {
+		ident := "Type" + goName(td.Type)
+		if err := declare(ident, "type "+td.Type); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "%s = %q\n", ident, td.Type)
+	}
+	b.WriteString(")\n")
+
+	for _, td := range tds {
+		if td.Relations == nil || len(*td.Relations) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n// Relations of %s.\nconst (\n", td.Type)
+		for _, rel := range sortedKeys(*td.Relations) {
+			ident := goName(td.Type) + goName(rel)
+			if !unicode.IsLetter(rune(ident[0])) {
+				ident = "X" + ident
+			}
+			if err := declare(ident, td.Type+"#"+rel); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "%s = %q\n", ident, rel)
+		}
+		b.WriteString(")\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+// goName converts a type or relation name to an exported Go identifier
+// fragment.
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '-' || r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
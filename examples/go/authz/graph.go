@@ -0,0 +1,141 @@
+package authz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// RenderGraphDOT renders the model as a Graphviz DOT graph of how relations
+// flow. Each type is a cluster holding a node per relation; an edge points
+// from whatever grants a relation to the relation it grants:
+//
+//   - solid edges for direct assignment, from the assignable type, userset
+//     or wildcard, labelled with any condition;
+//   - dashed edges for computed usersets ("viewer: editor");
+//   - dotted edges for tuple-to-userset ("viewer from parent"), from the
+//     relation on each parent type, labelled with the tupleset.
+//
+// Every branch of a union, intersection or exclusion is drawn. Edges from
+// an intersection are labelled "and" and the subtracted side of an
+// exclusion "but not" in red. Pipe the output to `dot -Tsvg`.
+func RenderGraphDOT(typeDefs []openfga.TypeDefinition) (string, error) {
+	if err := validateTypeDefs(typeDefs); err != nil {
+		return "", err
+	}
+	idx := indexTypeDefs(typeDefs)
+	g := &dotGraph{seen: map[string]bool{}}
+	for _, td := range typeDefs {
+		for _, name := range relationNames(td) {
+			g.userset(idx, td.Type, name, (*td.Relations)[name], "")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph model {\n  rankdir=LR;\n  node [shape=box, style=rounded];\n")
+	for _, td := range typeDefs {
+		fmt.Fprintf(&b, "\n  subgraph %s {\n    label=%s;\n", strconv.Quote("cluster_"+td.Type), strconv.Quote(td.Type))
+		fmt.Fprintf(&b, "    %s [shape=ellipse, style=solid];\n", strconv.Quote(td.Type))
+		for _, name := range relationNames(td) {
+			fmt.Fprintf(&b, "    %s [label=%s];\n", strconv.Quote(td.Type+"#"+name), strconv.Quote(name))
+		}
+		b.WriteString("  }\n")
+	}
+	if len(g.wildcards) > 0 {
+		b.WriteString("\n")
+		for _, w := range g.wildcards {
+			fmt.Fprintf(&b, "  %s [shape=ellipse, style=dashed];\n", strconv.Quote(w))
+		}
+	}
+	if len(g.edges) > 0 {
+		b.WriteString("\n")
+		for _, e := range g.edges {
+			b.WriteString("  " + e + "\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// dotGraph collects edges and wildcard nodes in the order they are found.
+type dotGraph struct {
+	edges     []string
+	wildcards []string
+	seen      map[string]bool
+}
+
+func (g *dotGraph) edge(from, to string, attrs ...string) {
+	e := fmt.Sprintf("%s -> %s", strconv.Quote(from), strconv.Quote(to))
+	if len(attrs) > 0 {
+		e += " [" + strings.Join(attrs, ", ") + "]"
+	}
+	e += ";"
+	if !g.seen[e] {
+		g.seen[e] = true
+		g.edges = append(g.edges, e)
+	}
+}
+
+// userset adds the edges of one rewrite of typ#relation. op is the label of
+// the enclosing operator, if any.
+func (g *dotGraph) userset(idx typeIndex, typ, relation string, us openfga.Userset, op string) {
+	to := typ + "#" + relation
+	attrs := func(style string, labels ...string) []string {
+		out := []string{"style=" + style}
+		if op != "" {
+			labels = append([]string{op}, labels...)
+		}
+		if len(labels) > 0 {
+			out = append(out, "label="+strconv.Quote(strings.Join(labels, " ")))
+		}
+		if op == "but not" {
+			out = append(out, "color=red")
+		}
+		return out
+	}
+
+	switch {
+	case us.This != nil:
+		for _, ref := range idx.directTypes(typ, relation) {
+			from := ref.Type
+			switch {
+			case ref.Wildcard != nil:
+				from += ":*"
+				if !g.seen[from] {
+					g.seen[from] = true
+					g.wildcards = append(g.wildcards, from)
+				}
+			case ref.Relation != nil && *ref.Relation != "":
+				from += "#" + *ref.Relation
+			}
+			var labels []string
+			if ref.Condition != nil && *ref.Condition != "" {
+				labels = append(labels, "with "+*ref.Condition)
+			}
+			g.edge(from, to, attrs("solid", labels...)...)
+		}
+	case us.ComputedUserset != nil:
+		g.edge(typ+"#"+us.ComputedUserset.GetRelation(), to, attrs("dashed")...)
+	case us.TupleToUserset != nil:
+		tupleset := us.TupleToUserset.Tupleset.GetRelation()
+		computed := us.TupleToUserset.ComputedUserset.GetRelation()
+		for _, parent := range idx.directTypes(typ, tupleset) {
+			if idx.hasRelation(parent.Type, computed) {
+				g.edge(parent.Type+"#"+computed, to, attrs("dotted", "from "+tupleset)...)
+			}
+		}
+	case us.Union != nil:
+		for _, child := range us.Union.Child {
+			g.userset(idx, typ, relation, child, op)
+		}
+	case us.Intersection != nil:
+		for _, child := range us.Intersection.Child {
+			g.userset(idx, typ, relation, child, "and")
+		}
+	case us.Difference != nil:
+		g.userset(idx, typ, relation, us.Difference.Base, op)
+		g.userset(idx, typ, relation, us.Difference.Subtract, "but not")
+	}
+}
@@ -0,0 +1,487 @@
+package authz
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	sdkcredentials "github.com/openfga/go-sdk/credentials"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Transport selects the protocol the Client speaks to the server.
+type Transport int
+
+const (
+	// TransportHTTP uses the HTTP/JSON API through the OpenFGA Go SDK.
+	TransportHTTP Transport = iota
+	// TransportGRPC uses the gRPC API, which the server exposes on a
+	// separate port (8081 by default). Config.APIURL then names the gRPC
+	// endpoint: "http://host:8081" for plaintext, "https://host:8081" for
+	// TLS.
+	TransportGRPC
+)
+
+// grpcFGA implements FGA over the OpenFGA gRPC API.
+//
+// Requests and responses are translated through the API's JSON encoding,
+// which the SDK types and the protobuf messages share, so that every field
+// the SDK can express reaches the server unchanged.
+type grpcFGA struct {
+	conn *grpc.ClientConn
+	svc  openfgav1.OpenFGAServiceClient
+}
+
+func newGRPCFGA(cfg Config, creds *sdkcredentials.Credentials) (*grpcFGA, error) {
+	u, err := url.Parse(cfg.APIURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid gRPC endpoint %q: expected http://host:port or https://host:port", cfg.APIURL)
+	}
+	var secure bool
+	switch u.Scheme {
+	case "http":
+	case "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("invalid gRPC endpoint %q: scheme must be http or https", cfg.APIURL)
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if secure {
+		opts[0] = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+	if ts, err := tokenSource(creds); err != nil {
+		return nil, err
+	} else if ts != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerCreds{ts: ts, secure: secure}))
+	}
+
+	conn, err := grpc.NewClient(u.Host, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", u.Host, err)
+	}
+	return &grpcFGA{conn: conn, svc: openfgav1.NewOpenFGAServiceClient(conn)}, nil
+}
+
+// tokenSource returns the bearer token source implied by creds, or nil for
+// an unauthenticated server.
+func tokenSource(creds *sdkcredentials.Credentials) (oauth2.TokenSource, error) {
+	if creds == nil {
+		return nil, nil
+	}
+	// NewCredentials validates the config and normalises the token issuer
+	// into a token endpoint URL, as the HTTP transport does.
+	creds, err := sdkcredentials.NewCredentials(*creds)
+	if err != nil {
+		return nil, err
+	}
+	switch creds.Method {
+	case sdkcredentials.CredentialsMethodApiToken:
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: creds.Config.ApiToken}), nil
+	case sdkcredentials.CredentialsMethodClientCredentials:
+		cc := clientcredentials.Config{
+			ClientID:     creds.Config.ClientCredentialsClientId,
+			ClientSecret: creds.Config.ClientCredentialsClientSecret,
+			TokenURL:     creds.Config.ClientCredentialsApiTokenIssuer,
+		}
+		if creds.Config.ClientCredentialsApiAudience != "" {
+			cc.EndpointParams = url.Values{"audience": {creds.Config.ClientCredentialsApiAudience}}
+		}
+		return cc.TokenSource(context.Background()), nil
+	}
+	return nil, nil
+}
+
+// bearerCreds attaches an OAuth2 bearer token to every RPC.
+type bearerCreds struct {
+	ts     oauth2.TokenSource
+	secure bool
+}
+
+func (b bearerCreds) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	tok, err := b.ts.Token()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + tok.AccessToken}, nil
+}
+
+// RequireTransportSecurity lets tokens be sent over plaintext only when the
+// endpoint was explicitly configured as http://, e.g. a local server.
+func (b bearerCreds) RequireTransportSecurity() bool {
+	return b.secure
+}
+
+// Close releases the gRPC connection.
+func (g *grpcFGA) Close() error {
+	return g.conn.Close()
+}
+
+func (g *grpcFGA) CreateStore(ctx context.Context, body client.ClientCreateStoreRequest) (*client.ClientCreateStoreResponse, error) {
+	resp, err := g.svc.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: body.Name})
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientCreateStoreResponse
+	return &out, fromProto(resp, &out)
+}
+
+func (g *grpcFGA) ListStores(ctx context.Context, opts client.ClientListStoresOptions) (*client.ClientListStoresResponse, error) {
+	req := &openfgav1.ListStoresRequest{ContinuationToken: deref(opts.ContinuationToken)}
+	if opts.PageSize != nil {
+		req.PageSize = wrapperspb.Int32(*opts.PageSize)
+	}
+	resp, err := g.svc.ListStores(ctx, req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientListStoresResponse
+	return &out, fromProto(resp, &out)
+}
+
+func (g *grpcFGA) DeleteStore(ctx context.Context, opts client.ClientDeleteStoreOptions) (*client.ClientDeleteStoreResponse, error) {
+	if _, err := g.svc.DeleteStore(ctx, &openfgav1.DeleteStoreRequest{StoreId: deref(opts.StoreId)}); err != nil {
+		return nil, grpcErr(err)
+	}
+	return &client.ClientDeleteStoreResponse{}, nil
+}
+
+func (g *grpcFGA) WriteAuthorizationModel(ctx context.Context, body client.ClientWriteAuthorizationModelRequest, opts client.ClientWriteAuthorizationModelOptions) (*client.ClientWriteAuthorizationModelResponse, error) {
+	var req openfgav1.WriteAuthorizationModelRequest
+	if err := toProto(body, &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	resp, err := g.svc.WriteAuthorizationModel(ctx, &req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientWriteAuthorizationModelResponse
+	return &out, fromProto(resp, &out)
+}
+
+func (g *grpcFGA) ReadAuthorizationModel(ctx context.Context, opts client.ClientReadAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error) {
+	resp, err := g.svc.ReadAuthorizationModel(ctx, &openfgav1.ReadAuthorizationModelRequest{
+		StoreId: deref(opts.StoreId),
+		Id:      deref(opts.AuthorizationModelId),
+	})
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientReadAuthorizationModelResponse
+	return &out, fromProto(resp, &out)
+}
+
+// ReadLatestAuthorizationModel reads the first page of models, which the
+// server returns newest first.
+func (g *grpcFGA) ReadLatestAuthorizationModel(ctx context.Context, opts client.ClientReadLatestAuthorizationModelOptions) (*client.ClientReadAuthorizationModelResponse, error) {
+	resp, err := g.svc.ReadAuthorizationModels(ctx, &openfgav1.ReadAuthorizationModelsRequest{
+		StoreId:  deref(opts.StoreId),
+		PageSize: wrapperspb.Int32(1),
+	})
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var models openfga.ReadAuthorizationModelsResponse
+	if err := fromProto(resp, &models); err != nil {
+		return nil, err
+	}
+	out := &client.ClientReadAuthorizationModelResponse{}
+	if len(models.AuthorizationModels) > 0 {
+		out.AuthorizationModel = &models.AuthorizationModels[0]
+	}
+	return out, nil
+}
+
+func (g *grpcFGA) Write(ctx context.Context, body client.ClientWriteRequest, opts client.ClientWriteOptions) (*client.ClientWriteResponse, error) {
+	if opts.Transaction != nil && opts.Transaction.Disable {
+		return nil, fmt.Errorf("grpc transport: non-transactional writes are not supported")
+	}
+	body2 := openfga.WriteRequest{AuthorizationModelId: opts.AuthorizationModelId}
+	if len(body.Writes) > 0 {
+		body2.Writes = &openfga.WriteRequestWrites{TupleKeys: body.Writes}
+	}
+	if len(body.Deletes) > 0 {
+		body2.Deletes = &openfga.WriteRequestDeletes{TupleKeys: body.Deletes}
+	}
+	var req openfgav1.WriteRequest
+	if err := toProto(body2, &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	if _, err := g.svc.Write(ctx, &req); err != nil {
+		return nil, grpcErr(err)
+	}
+
+	out := &client.ClientWriteResponse{}
+	for _, tk := range body.Writes {
+		out.Writes = append(out.Writes, client.ClientWriteRequestWriteResponse{TupleKey: tk, Status: client.SUCCESS})
+	}
+	for _, tk := range body.Deletes {
+		out.Deletes = append(out.Deletes, client.ClientWriteRequestDeleteResponse{TupleKey: tk, Status: client.SUCCESS})
+	}
+	return out, nil
+}
+
+func (g *grpcFGA) Read(ctx context.Context, body client.ClientReadRequest, opts client.ClientReadOptions) (*client.ClientReadResponse, error) {
+	body2 := openfga.ReadRequest{
+		PageSize:          opts.PageSize,
+		ContinuationToken: opts.ContinuationToken,
+		Consistency:       opts.Consistency,
+	}
+	if body.User != nil || body.Relation != nil || body.Object != nil {
+		body2.TupleKey = &openfga.ReadRequestTupleKey{User: body.User, Relation: body.Relation, Object: body.Object}
+	}
+	var req openfgav1.ReadRequest
+	if err := toProto(body2, &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	resp, err := g.svc.Read(ctx, &req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientReadResponse
+	return &out, fromProto(resp, &out)
+}
+
+func (g *grpcFGA) ReadChanges(ctx context.Context, body client.ClientReadChangesRequest, opts client.ClientReadChangesOptions) (*client.ClientReadChangesResponse, error) {
+	req := &openfgav1.ReadChangesRequest{
+		StoreId:           deref(opts.StoreId),
+		Type:              body.Type,
+		ContinuationToken: deref(opts.ContinuationToken),
+	}
+	if opts.PageSize != nil {
+		req.PageSize = wrapperspb.Int32(*opts.PageSize)
+	}
+	resp, err := g.svc.ReadChanges(ctx, req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientReadChangesResponse
+	return &out, fromProto(resp, &out)
+}
+
+func (g *grpcFGA) Check(ctx context.Context, body client.ClientCheckRequest, opts client.ClientCheckOptions) (*client.ClientCheckResponse, error) {
+	var req openfgav1.CheckRequest
+	if err := toProto(checkRequest(body, opts), &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	resp, err := g.svc.Check(ctx, &req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	allowed := resp.GetAllowed()
+	return &client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed}}, nil
+}
+
+func (g *grpcFGA) Expand(ctx context.Context, body client.ClientExpandRequest, opts client.ClientExpandOptions) (*client.ClientExpandResponse, error) {
+	var req openfgav1.ExpandRequest
+	err := toProto(openfga.ExpandRequest{
+		TupleKey:             openfga.ExpandRequestTupleKey{Relation: body.Relation, Object: body.Object},
+		AuthorizationModelId: opts.AuthorizationModelId,
+		Consistency:          opts.Consistency,
+	}, &req)
+	if err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	resp, err := g.svc.Expand(ctx, &req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientExpandResponse
+	return &out, fromProto(resp, &out)
+}
+
+func (g *grpcFGA) ListObjects(ctx context.Context, body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions) (*client.ClientListObjectsResponse, error) {
+	var req openfgav1.ListObjectsRequest
+	if err := toProto(listObjectsRequest(body, opts), &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	resp, err := g.svc.ListObjects(ctx, &req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return &client.ClientListObjectsResponse{Objects: resp.GetObjects()}, nil
+}
+
+func (g *grpcFGA) ListUsers(ctx context.Context, body client.ClientListUsersRequest, opts client.ClientListUsersOptions) (*client.ClientListUsersResponse, error) {
+	body2 := openfga.ListUsersRequest{
+		AuthorizationModelId: opts.AuthorizationModelId,
+		Object:               body.Object,
+		Relation:             body.Relation,
+		UserFilters:          body.UserFilters,
+		Context:              body.Context,
+		Consistency:          opts.Consistency,
+	}
+	if len(body.ContextualTuples) > 0 {
+		body2.ContextualTuples = &body.ContextualTuples
+	}
+	var req openfgav1.ListUsersRequest
+	if err := toProto(body2, &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	resp, err := g.svc.ListUsers(ctx, &req)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientListUsersResponse
+	return &out, fromProto(resp, &out)
+}
+
+func (g *grpcFGA) WriteAssertions(ctx context.Context, body client.ClientWriteAssertionsRequest, opts client.ClientWriteAssertionsOptions) (*client.ClientWriteAssertionsResponse, error) {
+	body2 := openfga.WriteAssertionsRequest{Assertions: make([]openfga.Assertion, len(body))}
+	for i, a := range body {
+		body2.Assertions[i] = a.ToAssertion()
+	}
+	var req openfgav1.WriteAssertionsRequest
+	if err := toProto(body2, &req); err != nil {
+		return nil, err
+	}
+	req.StoreId = deref(opts.StoreId)
+	req.AuthorizationModelId = deref(opts.AuthorizationModelId)
+	if _, err := g.svc.WriteAssertions(ctx, &req); err != nil {
+		return nil, grpcErr(err)
+	}
+	return &client.ClientWriteAssertionsResponse{}, nil
+}
+
+func (g *grpcFGA) ReadAssertions(ctx context.Context, opts client.ClientReadAssertionsOptions) (*client.ClientReadAssertionsResponse, error) {
+	resp, err := g.svc.ReadAssertions(ctx, &openfgav1.ReadAssertionsRequest{
+		StoreId:              deref(opts.StoreId),
+		AuthorizationModelId: deref(opts.AuthorizationModelId),
+	})
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	var out client.ClientReadAssertionsResponse
+	return &out, fromProto(resp, &out)
+}
+
+func contextualTupleKeys(tuples []client.ClientContextualTupleKey) *openfga.ContextualTupleKeys {
+	if len(tuples) == 0 {
+		return nil
+	}
+	return &openfga.ContextualTupleKeys{TupleKeys: tuples}
+}
+
+// toProto converts an SDK API model to the equivalent protobuf message via
+// their shared JSON encoding.
+func toProto(v interface{}, m proto.Message) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("grpc transport: encode request: %w", err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, m); err != nil {
+		return fmt.Errorf("grpc transport: encode request: %w", err)
+	}
+	return nil
+}
+
+// fromProto is the inverse of toProto.
+func fromProto(m proto.Message, v interface{}) error {
+	b, err := (protojson.MarshalOptions{}).Marshal(m)
+	if err != nil {
+		return fmt.Errorf("grpc transport: decode response: %w", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("grpc transport: decode response: %w", err)
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// grpcError carries a gRPC status together with the equivalent HTTP status
+// code and OpenFGA error code, so that retry and error classification work
+// the same for both transports.
+type grpcError struct {
+	err    error
+	code   int
+	reason string
+}
+
+func (e *grpcError) Error() string           { return e.err.Error() }
+func (e *grpcError) Unwrap() error           { return e.err }
+func (e *grpcError) ResponseStatusCode() int { return e.code }
+
+func grpcErr(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return &grpcError{err: err, code: httpStatus(st.Code()), reason: errorReason(st.Code())}
+}
+
+// errorReason returns the name of the OpenFGA error code c, as the HTTP
+// gateway reports it, or "" for a plain gRPC code.
+func errorReason(c codes.Code) string {
+	switch {
+	case c >= 2000 && c < 3000:
+		return openfgav1.ErrorCode(c).String()
+	case c >= 5000 && c < 6000:
+		return openfgav1.NotFoundErrorCode(c).String()
+	}
+	return ""
+}
+
+// httpStatus maps a gRPC code to the status the HTTP gateway would return.
+// OpenFGA reports its own error codes through the gRPC status too: 2xxx
+// codes are validation errors, 4xxx internal errors and 5xxx not found.
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	switch {
+	case c >= 2000 && c < 3000:
+		return http.StatusBadRequest
+	case c >= 5000 && c < 6000:
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
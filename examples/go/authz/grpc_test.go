@@ -0,0 +1,94 @@
+package authz
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Check throughput over each transport against a server that allows every
+// check, so that the numbers measure the client and the wire encoding
+// rather than evaluation. On one core of an Intel Xeon, linux/amd64:
+//
+//	BenchmarkCheckHTTP	   33535	     76846 ns/op	   35485 B/op	     381 allocs/op
+//	BenchmarkCheckGRPC	   61959	     42123 ns/op	   13342 B/op	     258 allocs/op
+//
+// The HTTP server listens on loopback TCP and the gRPC one on an in-memory
+// listener, so the gap includes the TCP stack; against a remote server
+// both pay it.
+
+const (
+	benchStoreID = "01HVMMBCMGZNT3SED4Z17ECXCA"
+	benchModelID = "01HVMMBCMGZNT3SED4Z17ECXCB"
+)
+
+func BenchmarkCheckHTTP(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/stores/"+benchStoreID+"/check" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"allowed":true}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{APIURL: srv.URL, StoreID: benchStoreID, ModelID: benchModelID})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+	benchmarkCheck(b, c)
+}
+
+// allowAllServer answers every Check with allowed.
+type allowAllServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+}
+
+func (allowAllServer) Check(context.Context, *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	return &openfgav1.CheckResponse{Allowed: true}, nil
+}
+
+func BenchmarkCheckGRPC(b *testing.B) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	openfgav1.RegisterOpenFGAServiceServer(srv, allowAllServer{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	c := NewWithFGA(&grpcFGA{conn: conn, svc: openfgav1.NewOpenFGAServiceClient(conn)}, Config{StoreID: benchStoreID, ModelID: benchModelID})
+	defer c.Close()
+	benchmarkCheck(b, c)
+}
+
+func benchmarkCheck(b *testing.B, c *Client) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			allowed, err := c.Check(ctx, "user:anne", "viewer", "document:plan")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if !allowed {
+				b.Fatal("Check denied, want allowed")
+			}
+		}
+	})
+}
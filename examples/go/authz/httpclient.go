@@ -0,0 +1,71 @@
+package authz
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/openfga/go-sdk/credentials"
+	"golang.org/x/oauth2"
+)
+
+// Connection pool defaults for the HTTP transport. net/http keeps only two
+// idle connections per host by default, so a client issuing many concurrent
+// Checks against a single server closes and redials connections constantly.
+const (
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
+	defaultKeepAlive       = 30 * time.Second
+)
+
+// newHTTPClient builds the http.Client the SDK sends requests through. The
+// SDK only applies credentials to a client it builds itself, so they are
+// attached here as an oauth2 transport instead.
+func newHTTPClient(cfg Config, creds *credentials.Credentials) (*http.Client, error) {
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = defaultMaxIdleConns
+	}
+	idleTimeout := cfg.IdleConnTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleConnTimeout
+	}
+	keepAlive := cfg.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = defaultKeepAlive
+	}
+
+	base := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: keepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdle,
+		MaxIdleConnsPerHost:   maxIdle,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       idleTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	ts, err := tokenSource(creds)
+	if err != nil {
+		return nil, err
+	}
+	if ts == nil {
+		return &http.Client{Transport: base}, nil
+	}
+	return &http.Client{Transport: &oauthTransport{Transport: oauth2.Transport{Source: ts, Base: base}, base: base}}, nil
+}
+
+// oauthTransport lets http.Client.CloseIdleConnections reach the pooled
+// transport beneath oauth2.Transport, which does not forward it.
+type oauthTransport struct {
+	oauth2.Transport
+	base *http.Transport
+}
+
+func (t *oauthTransport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
@@ -0,0 +1,217 @@
+package authz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// ImportFormat selects the input format of ImportTuples.
+type ImportFormat int
+
+const (
+	// ImportCSV reads rows of user,relation,object[,condition[,context]],
+	// where context is a JSON object of the condition's stored parameters.
+	// Lines starting with '#' and blank lines are skipped, as is a leading
+	// "user,relation,object" header row.
+	ImportCSV ImportFormat = iota
+	// ImportJSONLines reads one tuple key per line in the API's JSON form:
+	// {"user": ..., "relation": ..., "object": ..., "condition": {...}}.
+	// Blank lines are skipped.
+	ImportJSONLines
+)
+
+// ImportResult counts the outcome of ImportTuples.
+type ImportResult struct {
+	Written int
+	// Duplicates counts tuples skipped because they were already stored or
+	// appeared earlier in the input.
+	Duplicates int
+	// DryRun is set when the client is in dry-run mode: Written then counts
+	// tuples that would have been written.
+	DryRun bool
+	// Receipt records the chunks written and their server timestamps.
+	Receipt WriteReceipt
+}
+
+// ImportTuples reads tuples from r and writes the new ones through
+// WriteTuplesBatched. Each tuple is looked up before writing so that
+// re-running an import skips what is already stored; this costs one Read
+// per distinct tuple. Malformed input is reported with its line number and
+// nothing is written.
+//
+// Rows may carry a condition and its stored context. If any does, the
+// active model is read and every such row must name a condition the model
+// declares, with context keys among the condition's parameters; the first
+// row that does not is reported with its line number and nothing is
+// written.
+func (c *Client) ImportTuples(ctx context.Context, r io.Reader, format ImportFormat) (ImportResult, error) {
+	var (
+		tuples []client.ClientTupleKey
+		lines  []int
+		err    error
+	)
+	switch format {
+	case ImportCSV:
+		tuples, lines, err = readCSVTuples(r)
+	case ImportJSONLines:
+		tuples, lines, err = readJSONLTuples(r)
+	default:
+		err = fmt.Errorf("unknown format %d", format)
+	}
+	if err == nil {
+		err = c.checkImportConditions(ctx, tuples, lines)
+	}
+	if err != nil {
+		return ImportResult{DryRun: c.dryRun}, fmt.Errorf("import: %w", err)
+	}
+	return c.importTuples(ctx, tuples)
+}
+
+// checkImportConditions checks the conditions of imported tuples against
+// the active model; lines holds each tuple's input line.
+func (c *Client) checkImportConditions(ctx context.Context, tuples []client.ClientTupleKey, lines []int) error {
+	var conditions map[string]openfga.Condition
+	for i, tk := range tuples {
+		if tk.Condition == nil {
+			continue
+		}
+		if conditions == nil {
+			m, err := c.ActiveModel(ctx)
+			if err != nil {
+				return err
+			}
+			conditions = map[string]openfga.Condition{}
+			if m.Conditions != nil {
+				conditions = *m.Conditions
+			}
+		}
+		if reason := checkCondition(conditions, tk.Condition); reason != "" {
+			return fmt.Errorf("line %d: %s", lines[i], reason)
+		}
+	}
+	return nil
+}
+
+// importTuples writes the tuples that are not already stored.
+func (c *Client) importTuples(ctx context.Context, tuples []client.ClientTupleKey) (ImportResult, error) {
+	res := ImportResult{DryRun: c.dryRun}
+	seen := make(map[string]bool, len(tuples))
+	pending := make([]client.ClientTupleKey, 0, len(tuples))
+	for _, tk := range tuples {
+		k := tk.Object + "#" + tk.Relation + "@" + tk.User
+		if seen[k] {
+			res.Duplicates++
+			continue
+		}
+		seen[k] = true
+		ok, err := c.HasTuple(ctx, client.ClientTupleKeyWithoutCondition{User: tk.User, Relation: tk.Relation, Object: tk.Object})
+		if err != nil {
+			return res, fmt.Errorf("import: %w", err)
+		}
+		if ok {
+			res.Duplicates++
+			continue
+		}
+		pending = append(pending, tk)
+	}
+
+	receipt, err := c.WriteTuplesBatched(ctx, pending, BatchWriteOptions{})
+	res.Receipt = receipt
+	res.Written = receipt.Written()
+	if err != nil {
+		return res, fmt.Errorf("import: %w", err)
+	}
+	return res, nil
+}
+
+func readCSVTuples(r io.Reader) ([]client.ClientTupleKey, []int, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var (
+		tuples []client.ClientTupleKey
+		lines  []int
+	)
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return tuples, lines, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if first && len(rec) >= 3 && rec[0] == "user" && rec[1] == "relation" && rec[2] == "object" {
+			continue
+		}
+		if len(rec) < 3 || len(rec) > 5 {
+			return nil, nil, fmt.Errorf("line %d: expected user,relation,object[,condition[,context]], got %d fields", line, len(rec))
+		}
+		tk := client.ClientTupleKey{User: rec[0], Relation: rec[1], Object: rec[2]}
+		if len(rec) >= 4 && rec[3] != "" {
+			var ctxValues map[string]interface{}
+			if len(rec) == 5 && strings.TrimSpace(rec[4]) != "" {
+				if err := json.Unmarshal([]byte(rec[4]), &ctxValues); err != nil {
+					return nil, nil, fmt.Errorf("line %d: condition context: %w", line, err)
+				}
+			}
+			tk = ConditionalTuple(tk.User, tk.Relation, tk.Object, rec[3], ctxValues)
+		}
+		if err := checkImportedTuple(tk); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		tuples = append(tuples, tk)
+		lines = append(lines, line)
+	}
+}
+
+func readJSONLTuples(r io.Reader) ([]client.ClientTupleKey, []int, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		tuples []client.ClientTupleKey
+		lines  []int
+	)
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var tk client.ClientTupleKey
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&tk); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := checkImportedTuple(tk); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		tuples = append(tuples, tk)
+		lines = append(lines, line)
+	}
+	return tuples, lines, sc.Err()
+}
+
+func checkImportedTuple(tk client.ClientTupleKey) error {
+	switch {
+	case tk.User == "":
+		return fmt.Errorf("missing user")
+	case tk.Relation == "":
+		return fmt.Errorf("missing relation")
+	case tk.Object == "":
+		return fmt.Errorf("missing object")
+	}
+	return nil
+}
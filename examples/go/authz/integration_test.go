@@ -0,0 +1,101 @@
+//go:build integration
+
+package authz_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	"github.com/openfga/go-sdk/client"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// openfgaImage is the server the integration tests run against.
+const openfgaImage = "openfga/openfga:v1.8.1"
+
+// startServer runs an in-memory OpenFGA server in a container for the test
+// and returns its HTTP endpoint.
+func startServer(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        openfgaImage,
+			Cmd:          []string{"run"},
+			ExposedPorts: []string{"8080/tcp"},
+			WaitingFor:   wait.ForHTTP("/healthz").WithPort("8080/tcp"),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("start %s: %v", openfgaImage, err)
+	}
+	t.Cleanup(func() {
+		if err := ctr.Terminate(context.Background()); err != nil {
+			t.Errorf("stop %s: %v", openfgaImage, err)
+		}
+	})
+	endpoint, err := ctr.PortEndpoint(ctx, "8080/tcp", "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return endpoint
+}
+
+// TestGrantCheck writes the sample model and tuples to a real server and
+// checks direct, userset and inherited relations. Run it with
+//
+//	go test -tags integration ./authz
+//
+// on a machine with Docker.
+func TestGrantCheck(t *testing.T) {
+	ctx := context.Background()
+	fga, err := authz.New(authz.Config{APIURL: startServer(t), ValidateWrites: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fga.Close()
+
+	if _, err := fga.CreateStore(ctx, "integration"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fga.WriteModelFromFile(ctx, "../model.fga"); err != nil {
+		t.Fatal(err)
+	}
+	err = fga.Grant(ctx,
+		client.ClientTupleKey{User: "user:anne", Relation: "admin", Object: "organization:acme"},
+		client.ClientTupleKey{User: "user:bob", Relation: "member", Object: "organization:acme"},
+		client.ClientTupleKey{User: "organization:acme", Relation: "organization", Object: "project:api"},
+		client.ClientTupleKey{User: "organization:acme#member", Relation: "viewer", Object: "project:api"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                   string
+		user, relation, object string
+		want                   bool
+	}{
+		{"admin", "user:anne", "admin", "organization:acme", true},
+		{"member", "user:bob", "member", "organization:acme", true},
+		{"member is not admin", "user:bob", "admin", "organization:acme", false},
+		{"inherited admin", "user:anne", "admin", "project:api", true},
+		{"member not inherited as admin", "user:bob", "admin", "project:api", false},
+		{"viewer through membership", "user:bob", "viewer", "project:api", true},
+		{"stranger", "user:carol", "viewer", "project:api", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := fga.Check(ctx, tt.user, tt.relation, tt.object)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != tt.want {
+				t.Errorf("Check(%s %s %s) = %v, want %v", tt.user, tt.relation, tt.object, allowed, tt.want)
+			}
+		})
+	}
+}
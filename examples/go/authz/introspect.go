@@ -0,0 +1,187 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// RewriteKind classifies the top-level rewrite of a relation.
+type RewriteKind int
+
+const (
+	// RewriteDirect is a relation assigned only directly, e.g. "[user]".
+	RewriteDirect RewriteKind = iota
+	// RewriteComputed is another relation of the same object, e.g. "owner".
+	RewriteComputed
+	// RewriteTupleToUserset follows a relation to another object, e.g.
+	// "viewer from parent".
+	RewriteTupleToUserset
+	// RewriteUnion is "a or b".
+	RewriteUnion
+	// RewriteIntersection is "a and b".
+	RewriteIntersection
+	// RewriteExclusion is "a but not b".
+	RewriteExclusion
+)
+
+// Model is an authorization model with its relation metadata indexed once,
+// so that questions about types, relations and type restrictions are map
+// lookups rather than scans of the type definitions. A Model is immutable;
+// Client.Model returns the active model's, built once per model ID.
+type Model struct {
+	raw        *openfga.AuthorizationModel
+	idx        typeIndex
+	types      []string
+	relations  map[string][]string
+	assignable map[string][]string
+	rewrites   map[string]RewriteKind
+}
+
+// NewModel indexes typeDefs. The type definitions are not copied and must
+// not be modified afterwards.
+func NewModel(typeDefs []openfga.TypeDefinition) *Model {
+	return newModel(&openfga.AuthorizationModel{SchemaVersion: SchemaVersion, TypeDefinitions: typeDefs})
+}
+
+func newModel(m *openfga.AuthorizationModel) *Model {
+	cm := &Model{
+		raw:        m,
+		idx:        indexTypeDefs(m.TypeDefinitions),
+		types:      make([]string, 0, len(m.TypeDefinitions)),
+		relations:  make(map[string][]string, len(m.TypeDefinitions)),
+		assignable: map[string][]string{},
+		rewrites:   map[string]RewriteKind{},
+	}
+	for _, td := range m.TypeDefinitions {
+		cm.types = append(cm.types, td.Type)
+		names := relationNames(td)
+		cm.relations[td.Type] = names
+		for _, name := range names {
+			key := td.Type + "#" + name
+			refs := cm.idx.directTypes(td.Type, name)
+			restrictions := make([]string, len(refs))
+			for i, ref := range refs {
+				restrictions[i] = refKey(ref)
+			}
+			cm.assignable[key] = restrictions
+			cm.rewrites[key] = rewriteKind((*td.Relations)[name])
+		}
+	}
+	sort.Strings(cm.types)
+	return cm
+}
+
+func rewriteKind(us openfga.Userset) RewriteKind {
+	switch {
+	case us.ComputedUserset != nil:
+		return RewriteComputed
+	case us.TupleToUserset != nil:
+		return RewriteTupleToUserset
+	case us.Union != nil:
+		return RewriteUnion
+	case us.Intersection != nil:
+		return RewriteIntersection
+	case us.Difference != nil:
+		return RewriteExclusion
+	}
+	return RewriteDirect
+}
+
+// ID returns the model's ID, or "" for a Model built with NewModel.
+func (m *Model) ID() string {
+	return m.raw.Id
+}
+
+// TypeDefinitions returns the model's type definitions. They must not be
+// modified.
+func (m *Model) TypeDefinitions() []openfga.TypeDefinition {
+	return m.raw.TypeDefinitions
+}
+
+// Types returns the types the model defines, sorted by name.
+func (m *Model) Types() []string {
+	return append([]string(nil), m.types...)
+}
+
+// HasType reports whether the model defines typ.
+func (m *Model) HasType(typ string) bool {
+	return m.idx.hasType(typ)
+}
+
+// HasRelation reports whether typ defines relation.
+func (m *Model) HasRelation(typ, relation string) bool {
+	return m.idx.hasRelation(typ, relation)
+}
+
+// Relations returns the relations typ defines, sorted by name, or nil for
+// an undefined type.
+func (m *Model) Relations(typ string) []string {
+	return append([]string(nil), m.relations[typ]...)
+}
+
+// AssignableTypes returns the type restrictions of typ#relation in model
+// order and in DSL form; see Client.AssignableTypes. It is empty for a
+// relation that cannot be assigned directly or is not defined.
+func (m *Model) AssignableTypes(typ, relation string) []string {
+	return append([]string(nil), m.assignable[typ+"#"+relation]...)
+}
+
+// Rewrite returns the kind of typ#relation's top-level rewrite, and false
+// if the relation is not defined.
+func (m *Model) Rewrite(typ, relation string) (RewriteKind, bool) {
+	k, ok := m.rewrites[typ+"#"+relation]
+	return k, ok
+}
+
+// ValidateTuples is ValidateTuples against m.
+func (m *Model) ValidateTuples(tuples []client.ClientTupleKey) error {
+	return m.idx.validateTuples(tuples)
+}
+
+// Model returns the active model, indexed. It follows the client's pinned
+// model: after PinModel or a refresh to another model it returns that one.
+func (c *Client) Model(ctx context.Context) (*Model, error) {
+	id, err := c.resolveModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if id == nil {
+		return nil, fmt.Errorf("%w: store %s has none", ErrModelNotFound, c.StoreID)
+	}
+	return c.indexedModel(ctx, *id)
+}
+
+// RelationsForType returns the relations objType defines in the active
+// model, sorted by name.
+func (c *Client) RelationsForType(ctx context.Context, objType string) ([]string, error) {
+	m, err := c.Model(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !m.HasType(objType) {
+		return nil, fmt.Errorf("authz: type %q is not defined", objType)
+	}
+	return m.Relations(objType), nil
+}
+
+// AssignableTypes returns the type restrictions of objType#relation in the
+// active model, in model order and in DSL form: "user", "user:*",
+// "group#member", or "user with cond" for a conditional restriction. The
+// result is empty for a relation that cannot be assigned directly.
+func (c *Client) AssignableTypes(ctx context.Context, objType, relation string) ([]string, error) {
+	m, err := c.Model(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !m.HasType(objType) {
+		return nil, fmt.Errorf("authz: type %q is not defined", objType)
+	}
+	if !m.HasRelation(objType, relation) {
+		return nil, fmt.Errorf("authz: relation %q is not defined on type %q", relation, objType)
+	}
+	return m.AssignableTypes(objType, relation), nil
+}
@@ -0,0 +1,64 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// lazyInit runs Config.Bootstrap before the client's first call. A
+// successful run is kept for the life of the client; a failed one is
+// reported to every call that waited on it, and the next call tries again.
+type lazyInit struct {
+	manifest Manifest
+
+	mu      sync.Mutex
+	ok      bool
+	attempt *initAttempt
+}
+
+// initAttempt is one run of the bootstrap; err is set before done closes.
+type initAttempt struct {
+	done chan struct{}
+	err  error
+}
+
+// initKey marks the context of the calls the bootstrap itself makes.
+type initKey struct{}
+
+// ensureInit bootstraps the client on first use, or waits for the run
+// another goroutine started. It returns ctx.Err() if ctx ends first; the
+// run itself continues under the context of the call that started it.
+func (c *Client) ensureInit(ctx context.Context) error {
+	l := c.lazy
+	if l == nil || ctx.Value(initKey{}) != nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.ok {
+		l.mu.Unlock()
+		return nil
+	}
+	if a := l.attempt; a != nil {
+		l.mu.Unlock()
+		select {
+		case <-a.done:
+			return a.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	a := &initAttempt{done: make(chan struct{})}
+	l.attempt = a
+	l.mu.Unlock()
+
+	if _, err := c.Bootstrap(context.WithValue(ctx, initKey{}, true), l.manifest); err != nil {
+		a.err = fmt.Errorf("authz: lazy initialization: %w", err)
+	}
+	l.mu.Lock()
+	l.ok = a.err == nil
+	l.attempt = nil
+	l.mu.Unlock()
+	close(a.done)
+	return a.err
+}
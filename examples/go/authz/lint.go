@@ -0,0 +1,223 @@
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// Lint rule IDs, for reading LintFinding.Rule and for suppressing a rule in
+// LintModel.
+const (
+	// LintUnusedRelation flags a relation no rewrite or type restriction
+	// refers to. Relations the application checks directly are expected
+	// to trip it, so it is informational.
+	LintUnusedRelation = "unused-relation"
+	// LintEmptyType flags a type with no relations that no type
+	// restriction admits either, so it plays no part in the model.
+	LintEmptyType = "empty-type"
+	// LintPublicSensitive flags a wildcard such as "user:*" assignable to a
+	// relation whose name suggests it grants more than read access, e.g.
+	// owner, admin, editor or can_delete.
+	LintPublicSensitive = "public-sensitive"
+	// LintUnreachableRewrite flags a rewrite branch that can never grant
+	// anything or repeats a sibling: "x but not x", a duplicated operand,
+	// or "rel from parent" where a parent type lacks rel.
+	LintUnreachableRewrite = "unreachable-rewrite"
+)
+
+// LintSeverity ranks a LintFinding. Findings are never errors: the model is
+// valid, only questionable.
+type LintSeverity int
+
+const (
+	LintInfo LintSeverity = iota
+	LintWarning
+)
+
+func (s LintSeverity) String() string {
+	if s == LintWarning {
+		return "warning"
+	}
+	return "info"
+}
+
+// LintFinding is one smell LintModel found. Relation is empty for findings
+// about a whole type.
+type LintFinding struct {
+	Rule     string
+	Severity LintSeverity
+	Type     string
+	Relation string
+	Message  string
+}
+
+func (f LintFinding) String() string {
+	loc := f.Type
+	if f.Relation != "" {
+		loc += "#" + f.Relation
+	}
+	return fmt.Sprintf("%s %s %s: %s", f.Severity, f.Rule, loc, f.Message)
+}
+
+// sensitiveRelationWords mark relation names that LintPublicSensitive
+// treats as granting more than read access.
+var sensitiveRelationWords = []string{"owner", "admin", "edit", "write", "delete", "manage", "create", "update"}
+
+// LintModel reports style problems and anti-patterns in a valid model, in
+// type order and then by relation name; see the Lint* rule IDs. Rules named
+// in suppress are skipped. The error is reserved for a model that fails
+// validation, which is a problem of a different kind.
+func LintModel(typeDefs []openfga.TypeDefinition, suppress ...string) ([]LintFinding, error) {
+	if err := validateTypeDefs(typeDefs); err != nil {
+		return nil, err
+	}
+	l := &linter{idx: indexTypeDefs(typeDefs), skip: map[string]bool{}, used: map[string]bool{}, admitted: map[string]bool{}}
+	for _, rule := range suppress {
+		l.skip[rule] = true
+	}
+	for _, td := range typeDefs {
+		for _, name := range relationNames(td) {
+			for _, ref := range l.idx.directTypes(td.Type, name) {
+				l.admitted[ref.Type] = true
+				if ref.Relation != nil && *ref.Relation != "" {
+					l.used[ref.Type+"#"+*ref.Relation] = true
+				}
+			}
+			l.markUsed(td.Type, (*td.Relations)[name])
+		}
+	}
+
+	for _, td := range typeDefs {
+		names := relationNames(td)
+		if len(names) == 0 && !l.admitted[td.Type] {
+			l.report(LintEmptyType, LintWarning, td.Type, "", "type has no relations and no type restriction admits it")
+		}
+		for _, name := range names {
+			if !l.used[td.Type+"#"+name] {
+				l.report(LintUnusedRelation, LintInfo, td.Type, name, "relation is not referenced by any other relation")
+			}
+			l.lintPublic(td.Type, name)
+			l.lintRewrite(td.Type, name, (*td.Relations)[name])
+		}
+	}
+	return l.findings, nil
+}
+
+type linter struct {
+	idx      typeIndex
+	skip     map[string]bool
+	used     map[string]bool
+	admitted map[string]bool
+	findings []LintFinding
+}
+
+func (l *linter) report(rule string, sev LintSeverity, typ, relation, msg string) {
+	if !l.skip[rule] {
+		l.findings = append(l.findings, LintFinding{Rule: rule, Severity: sev, Type: typ, Relation: relation, Message: msg})
+	}
+}
+
+// markUsed records the relations a rewrite of typ refers to.
+func (l *linter) markUsed(typ string, us openfga.Userset) {
+	switch {
+	case us.ComputedUserset != nil:
+		l.used[typ+"#"+us.ComputedUserset.GetRelation()] = true
+	case us.TupleToUserset != nil:
+		tupleset := us.TupleToUserset.Tupleset.GetRelation()
+		l.used[typ+"#"+tupleset] = true
+		for _, parent := range l.idx.directTypes(typ, tupleset) {
+			l.used[parent.Type+"#"+us.TupleToUserset.ComputedUserset.GetRelation()] = true
+		}
+	case us.Union != nil:
+		for _, child := range us.Union.Child {
+			l.markUsed(typ, child)
+		}
+	case us.Intersection != nil:
+		for _, child := range us.Intersection.Child {
+			l.markUsed(typ, child)
+		}
+	case us.Difference != nil:
+		l.markUsed(typ, us.Difference.Base)
+		l.markUsed(typ, us.Difference.Subtract)
+	}
+}
+
+func (l *linter) lintPublic(typ, relation string) {
+	lower := strings.ToLower(relation)
+	sensitive := false
+	for _, w := range sensitiveRelationWords {
+		if strings.Contains(lower, w) {
+			sensitive = true
+			break
+		}
+	}
+	if !sensitive {
+		return
+	}
+	for _, ref := range l.idx.directTypes(typ, relation) {
+		if ref.Wildcard != nil {
+			l.report(LintPublicSensitive, LintWarning, typ, relation,
+				fmt.Sprintf("%q lets every %s hold a relation that grants more than read access", refKey(ref), ref.Type))
+		}
+	}
+}
+
+// lintRewrite looks for branches of a rewrite of typ#relation that cannot
+// grant anything or repeat a sibling.
+func (l *linter) lintRewrite(typ, relation string, us openfga.Userset) {
+	switch {
+	case us.TupleToUserset != nil:
+		tupleset := us.TupleToUserset.Tupleset.GetRelation()
+		rel := us.TupleToUserset.ComputedUserset.GetRelation()
+		for _, parent := range l.idx.directTypes(typ, tupleset) {
+			if !l.idx.hasRelation(parent.Type, rel) {
+				l.report(LintUnreachableRewrite, LintWarning, typ, relation,
+					fmt.Sprintf("%q from %q never resolves through %s, which does not define %q", rel, tupleset, parent.Type, rel))
+			}
+		}
+	case us.Union != nil:
+		l.lintChildren(typ, relation, "or", us.Union.Child)
+	case us.Intersection != nil:
+		l.lintChildren(typ, relation, "and", us.Intersection.Child)
+	case us.Difference != nil:
+		if sameUserset(us.Difference.Base, us.Difference.Subtract) {
+			l.report(LintUnreachableRewrite, LintWarning, typ, relation,
+				fmt.Sprintf("%q subtracts itself and never grants anything", l.render(typ, relation, us)))
+		}
+		l.lintRewrite(typ, relation, us.Difference.Base)
+		l.lintRewrite(typ, relation, us.Difference.Subtract)
+	}
+}
+
+func (l *linter) lintChildren(typ, relation, op string, children []openfga.Userset) {
+	for i, child := range children {
+		for _, prev := range children[:i] {
+			if sameUserset(prev, child) {
+				l.report(LintUnreachableRewrite, LintWarning, typ, relation,
+					fmt.Sprintf("operand %q of %q is repeated", l.render(typ, relation, child), op))
+				break
+			}
+		}
+		l.lintRewrite(typ, relation, child)
+	}
+}
+
+// render returns us in DSL form for a message.
+func (l *linter) render(typ, relation string, us openfga.Userset) string {
+	s, err := renderUserset(us, l.idx.directTypes(typ, relation), false)
+	if err != nil {
+		return "?"
+	}
+	return s
+}
+
+// sameUserset reports whether a and b are the same rewrite, ignoring
+// operand order.
+func sameUserset(a, b openfga.Userset) bool {
+	ja, errA := json.Marshal(canonicalUserset(a))
+	jb, errB := json.Marshal(canonicalUserset(b))
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
@@ -0,0 +1,20 @@
+package authz
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardHandler drops every record without formatting it.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// logger returns the client's logger annotated with the store and model the
+// client is bound to.
+func (c *Client) logger() *slog.Logger {
+	return c.log.With("store_id", c.StoreID, "model_id", c.ModelID())
+}
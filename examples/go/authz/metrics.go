@@ -0,0 +1,18 @@
+package authz
+
+import "time"
+
+// Metrics receives one observation per API operation. ObserveCall is
+// invoked once per logical call, after retries, with the operation name
+// ("Check", "Write", ...), the total time including backoff, and the final
+// error. Implementations must be safe for concurrent use.
+//
+// Package authz/prommetrics provides a Prometheus implementation.
+type Metrics interface {
+	ObserveCall(op string, dur time.Duration, err error)
+}
+
+// NopMetrics discards all observations. It is the default.
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveCall(string, time.Duration, error) {}
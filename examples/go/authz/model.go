@@ -0,0 +1,284 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// WriteModel parses a model written in the OpenFGA DSL and writes it to the
+// active store. See WriteModelRequest.
+func (c *Client) WriteModel(ctx context.Context, dsl string) (string, error) {
+	model, err := ModelFromDSL(dsl, "")
+	if err != nil {
+		return "", err
+	}
+	return c.WriteModelRequest(ctx, model)
+}
+
+// WriteModelFromFile reads a model in the OpenFGA DSL from the .fga file at
+// path and writes it to the active store. See WriteModel.
+func (c *Client) WriteModelFromFile(ctx context.Context, path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read model: %w", err)
+	}
+	id, err := c.WriteModel(ctx, string(b))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return id, nil
+}
+
+// modelFileNames are the conventional model file names, in order of
+// preference.
+var modelFileNames = []string{"model.fga", "authorization-model.fga"}
+
+// ErrModelFileNotFound is returned by DiscoverModelFile when no directory
+// it searched holds a model file.
+var ErrModelFileNotFound = errors.New("authz: model file not found")
+
+// DiscoverModelFile looks for model.fga, then authorization-model.fga, in
+// startDir and then in each parent directory, the way tools locate their
+// config files, and returns the path of the first one found. The search
+// stops after the repository root, the first directory containing .git, or
+// at the filesystem root. An empty startDir means the working directory.
+func DiscoverModelFile(startDir string) (string, error) {
+	if startDir == "" {
+		startDir = "."
+	}
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("discover model file: %w", err)
+	}
+	for {
+		for _, name := range modelFileNames {
+			path := filepath.Join(dir, name)
+			if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+				return path, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil || parent == dir {
+			return "", fmt.Errorf("%w: no %s in %s or its parents up to %s",
+				ErrModelFileNotFound, strings.Join(modelFileNames, " or "), startDir, dir)
+		}
+		dir = parent
+	}
+}
+
+// WriteModelFromReader reads a model in the OpenFGA DSL from r and writes it
+// to the active store. It suits models embedded with //go:embed. See
+// WriteModel.
+func (c *Client) WriteModelFromReader(ctx context.Context, r io.Reader) (string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("read model: %w", err)
+	}
+	return c.WriteModel(ctx, string(b))
+}
+
+// WriteModelRequest writes an authorization model to the active store and
+// pins the client to the returned model ID. If the store's latest model is
+// semantically identical to model, nothing is written and the existing ID is
+// returned, so re-running a deploy does not grow the model history. The
+// model must use schema SchemaVersion. A model the server rejects is
+// reported as a *ModelValidationError.
+func (c *Client) WriteModelRequest(ctx context.Context, model client.ClientWriteAuthorizationModelRequest) (string, error) {
+	if err := checkSchemaVersion(model.SchemaVersion); err != nil {
+		return "", fmt.Errorf("write authorization model: %w", err)
+	}
+	latest, err := c.latestModel(ctx)
+	if err != nil {
+		return "", err
+	}
+	if latest != nil && modelsEqual(model, client.ClientWriteAuthorizationModelRequest{
+		SchemaVersion:   latest.SchemaVersion,
+		TypeDefinitions: latest.TypeDefinitions,
+		Conditions:      latest.Conditions,
+	}) {
+		c.PinModel(latest.Id)
+		c.logger().Info("authorization model unchanged; not writing", "operation", "WriteAuthorizationModel")
+		return latest.Id, nil
+	}
+
+	var resp *client.ClientWriteAuthorizationModelResponse
+	err = c.call(ctx, "WriteAuthorizationModel", func(ctx context.Context) (err error) {
+		resp, err = c.fga.WriteAuthorizationModel(ctx, model, client.ClientWriteAuthorizationModelOptions{StoreId: &c.StoreID})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("write authorization model: %w", modelError(err))
+	}
+	c.PinModel(resp.AuthorizationModelId)
+	c.logger().Info("wrote authorization model", "operation", "WriteAuthorizationModel")
+	return resp.AuthorizationModelId, nil
+}
+
+// RollbackModel makes an earlier model of the active store current again.
+// Models are immutable, so the target is read and written anew as the
+// latest model; the new model ID is returned and the client is pinned to
+// it. If the target is already semantically the latest model, nothing is
+// written and the latest ID is returned. The target must belong to the
+// active store.
+func (c *Client) RollbackModel(ctx context.Context, targetModelID string) (string, error) {
+	if targetModelID == "" {
+		return "", fmt.Errorf("rollback model: target model ID is required")
+	}
+	target, err := c.readModel(ctx, targetModelID)
+	if err != nil {
+		return "", fmt.Errorf("rollback model: %w", err)
+	}
+	id, err := c.WriteModelRequest(ctx, client.ClientWriteAuthorizationModelRequest{
+		SchemaVersion:   target.SchemaVersion,
+		TypeDefinitions: target.TypeDefinitions,
+		Conditions:      target.Conditions,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rollback model to %s: %w", targetModelID, err)
+	}
+	c.logger().Info("rolled back authorization model", "operation", "RollbackModel", "target_model_id", targetModelID)
+	return id, nil
+}
+
+// LatestModelID returns the ID of the store's most recent model, or "" if
+// the store has no model yet.
+func (c *Client) LatestModelID(ctx context.Context) (string, error) {
+	m, err := c.latestModel(ctx)
+	if err != nil || m == nil {
+		return "", err
+	}
+	return m.Id, nil
+}
+
+// latestModel returns the store's most recent model, or nil if it has none.
+func (c *Client) latestModel(ctx context.Context) (*openfga.AuthorizationModel, error) {
+	var resp *client.ClientReadAuthorizationModelResponse
+	err := c.call(ctx, "ReadLatestAuthorizationModel", func(ctx context.Context) (err error) {
+		resp, err = c.fga.ReadLatestAuthorizationModel(ctx, client.ClientReadLatestAuthorizationModelOptions{StoreId: &c.StoreID})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read latest authorization model: %w", err)
+	}
+	return resp.AuthorizationModel, nil
+}
+
+// ActiveModel returns the model queries are pinned to, resolving the latest
+// model first if none is pinned. Models are immutable, so each one is read
+// from the server only once.
+func (c *Client) ActiveModel(ctx context.Context) (*openfga.AuthorizationModel, error) {
+	m, err := c.Model(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.raw, nil
+}
+
+// readModel returns the model with the given ID.
+func (c *Client) readModel(ctx context.Context, id string) (*openfga.AuthorizationModel, error) {
+	m, err := c.indexedModel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return m.raw, nil
+}
+
+// indexedModel returns the model with the given ID, reading and indexing it
+// on first use. Models never change, so each is kept for the client's
+// lifetime.
+func (c *Client) indexedModel(ctx context.Context, id string) (*Model, error) {
+	c.mu.RLock()
+	m, ok := c.models[id]
+	c.mu.RUnlock()
+	if ok {
+		return m, nil
+	}
+
+	var resp *client.ClientReadAuthorizationModelResponse
+	err := c.call(ctx, "ReadAuthorizationModel", func(ctx context.Context) (err error) {
+		resp, err = c.fga.ReadAuthorizationModel(ctx, client.ClientReadAuthorizationModelOptions{
+			AuthorizationModelId: &id,
+			StoreId:              &c.StoreID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read authorization model %s: %w", id, err)
+	}
+	if resp.AuthorizationModel == nil {
+		return nil, fmt.Errorf("read authorization model %s: empty response", id)
+	}
+
+	m = newModel(resp.AuthorizationModel)
+	c.mu.Lock()
+	if c.models == nil {
+		c.models = map[string]*Model{}
+	}
+	c.models[id] = m
+	c.mu.Unlock()
+	return m, nil
+}
+
+// validateWrites rejects tuples whose user is malformed. With
+// Config.ValidateWrites every tuple is then checked against modelID, or the
+// active model if it is empty; without it only wildcard grants are, so that
+// a public grant the model does not allow fails with a precise error. A
+// non-empty modelID must name a model of the store.
+func (c *Client) validateWrites(ctx context.Context, tuples []client.ClientTupleKey, modelID string) (err error) {
+	defer func() { err = c.redactErr(err, writeValues(client.ClientWriteRequest{Writes: tuples})...) }()
+	var (
+		verr      TupleValidationError
+		wildcards []int
+	)
+	for i, tk := range tuples {
+		u, err := ParseUser(tk.User)
+		switch {
+		case err != nil:
+			verr.Problems = append(verr.Problems, TupleProblem{Index: i, Tuple: tk, Reason: err.Error()})
+		case u.Kind() == UserWildcard:
+			wildcards = append(wildcards, i)
+		}
+	}
+	if len(verr.Problems) > 0 {
+		return &verr
+	}
+	if !c.validate && len(wildcards) == 0 && modelID == "" {
+		return nil
+	}
+
+	var m *Model
+	if modelID != "" {
+		m, err = c.indexedModel(ctx, modelID)
+	} else {
+		m, err = c.Model(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if !c.validate && len(wildcards) == 0 {
+		return nil
+	}
+	if c.validate {
+		return m.ValidateTuples(tuples)
+	}
+	subset := make([]client.ClientTupleKey, len(wildcards))
+	for i, idx := range wildcards {
+		subset[i] = tuples[idx]
+	}
+	err = m.ValidateTuples(subset)
+	var subErr *TupleValidationError
+	if errors.As(err, &subErr) {
+		for i := range subErr.Problems {
+			subErr.Problems[i].Index = wildcards[subErr.Problems[i].Index]
+		}
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package authz_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	"github.com/bogdanticu88/openfga-examples/authz/testutil"
+	"github.com/openfga/go-sdk/client"
+)
+
+// TestConditionalCheck writes a model declaring a condition and checks a
+// grant gated by it with contexts that pass and fail the condition.
+func TestConditionalCheck(t *testing.T) {
+	fga := testutil.LoadFixture(t, `model
+  schema 1.1
+
+type user
+
+type document
+  relations
+    define viewer: [user with non_expired]
+
+condition non_expired(current_time: timestamp, valid_until: timestamp) {
+  current_time < valid_until
+}
+`, []client.ClientTupleKey{
+		authz.ConditionalTuple("user:anne", "viewer", "document:plan", "non_expired",
+			map[string]interface{}{"valid_until": "2030-01-01T00:00:00Z"}),
+	})
+	tests := []struct {
+		name string
+		now  string
+		want bool
+	}{
+		{"before expiry", "2026-10-14T09:00:00Z", true},
+		{"after expiry", "2030-01-01T00:00:01Z", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := fga.Check(context.Background(), "user:anne", "viewer", "document:plan",
+				authz.WithContext(map[string]interface{}{"current_time": tt.now}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != tt.want {
+				t.Errorf("Check at %s = %v, want %v", tt.now, allowed, tt.want)
+			}
+		})
+	}
+}
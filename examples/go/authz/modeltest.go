@@ -0,0 +1,205 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openfga/go-sdk/client"
+	"gopkg.in/yaml.v3"
+)
+
+// modelTestFile is the OpenFGA CLI test format (.fga.yaml). Only check
+// assertions are run; other assertion kinds are ignored.
+type modelTestFile struct {
+	Name      string           `yaml:"name"`
+	Model     string           `yaml:"model"`
+	ModelFile string           `yaml:"model_file"`
+	Tuples    []modelTestTuple `yaml:"tuples"`
+	Tests     []modelTest      `yaml:"tests"`
+}
+
+type modelTest struct {
+	Name   string           `yaml:"name"`
+	Tuples []modelTestTuple `yaml:"tuples"`
+	Check  []modelTestCheck `yaml:"check"`
+}
+
+type modelTestCheck struct {
+	User             string                 `yaml:"user"`
+	Object           string                 `yaml:"object"`
+	Context          map[string]interface{} `yaml:"context"`
+	ContextualTuples []modelTestTuple       `yaml:"contextual_tuples"`
+	Assertions       map[string]bool        `yaml:"assertions"`
+}
+
+type modelTestTuple struct {
+	User      string `yaml:"user"`
+	Relation  string `yaml:"relation"`
+	Object    string `yaml:"object"`
+	Condition *struct {
+		Name    string                 `yaml:"name"`
+		Context map[string]interface{} `yaml:"context"`
+	} `yaml:"condition"`
+}
+
+func (t modelTestTuple) key() client.ClientTupleKey {
+	if t.Condition != nil {
+		return ConditionalTuple(t.User, t.Relation, t.Object, t.Condition.Name, t.Condition.Context)
+	}
+	return client.ClientTupleKey{User: t.User, Relation: t.Relation, Object: t.Object}
+}
+
+// AssertionResult is the outcome of one check assertion.
+type AssertionResult struct {
+	Test     string
+	User     string
+	Relation string
+	Object   string
+	Expected bool
+	Got      bool
+	// Err is set when the check itself failed; the assertion then counts
+	// as failed.
+	Err error
+}
+
+// Passed reports whether the check succeeded and matched the expectation.
+func (r AssertionResult) Passed() bool {
+	return r.Err == nil && r.Got == r.Expected
+}
+
+func (r AssertionResult) String() string {
+	status := "PASS"
+	if !r.Passed() {
+		status = "FAIL"
+	}
+	s := fmt.Sprintf("%s %s: %s %s %s: want %t", status, r.Test, r.User, r.Relation, r.Object, r.Expected)
+	if r.Err != nil {
+		return s + ", error: " + r.Err.Error()
+	}
+	return fmt.Sprintf("%s, got %t", s, r.Got)
+}
+
+// TestReport collects the results of RunModelTests.
+type TestReport struct {
+	Name    string
+	Results []AssertionResult
+	Passed  int
+	Failed  int
+}
+
+// RunModelTests runs the check assertions in an OpenFGA .fga.yaml test file.
+// Each test gets a temporary store holding the file's model, the file's
+// tuples and the test's own tuples; the store is deleted afterwards.
+//
+// Assertion failures are recorded in the report rather than returned, so a
+// CI wrapper should fail the build when Failed is nonzero. The error is
+// reserved for problems running the file at all, such as an invalid model.
+func (c *Client) RunModelTests(ctx context.Context, path string) (TestReport, error) {
+	var report TestReport
+	file, dsl, err := loadModelTestFile(path)
+	if err != nil {
+		return report, err
+	}
+	report.Name = file.Name
+
+	for _, test := range file.Tests {
+		results, err := c.runModelTest(ctx, dsl, file.Tuples, test)
+		if err != nil {
+			return report, fmt.Errorf("model tests %s: test %q: %w", path, test.Name, err)
+		}
+		for _, r := range results {
+			if r.Passed() {
+				report.Passed++
+			} else {
+				report.Failed++
+			}
+		}
+		report.Results = append(report.Results, results...)
+	}
+	return report, nil
+}
+
+// loadModelTestFile parses the test file at path and returns it with its
+// model DSL, read from model_file if set.
+func loadModelTestFile(path string) (modelTestFile, string, error) {
+	var file modelTestFile
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return file, "", fmt.Errorf("model tests: %w", err)
+	}
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return file, "", fmt.Errorf("model tests %s: %w", path, err)
+	}
+
+	dsl := file.Model
+	if file.ModelFile != "" {
+		if dsl != "" {
+			return file, "", fmt.Errorf("model tests %s: model and model_file are mutually exclusive", path)
+		}
+		b, err := os.ReadFile(filepath.Join(filepath.Dir(path), file.ModelFile))
+		if err != nil {
+			return file, "", fmt.Errorf("model tests %s: %w", path, err)
+		}
+		dsl = string(b)
+	}
+	if dsl == "" {
+		return file, "", fmt.Errorf("model tests %s: no model", path)
+	}
+	return file, dsl, nil
+}
+
+func (c *Client) runModelTest(ctx context.Context, dsl string, tuples []modelTestTuple, test modelTest) (_ []AssertionResult, err error) {
+	tmp := c.withStore("")
+	if _, err := tmp.CreateStore(ctx, "model-test-"+test.Name); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if derr := tmp.DeleteStore(ctx, tmp.StoreID); err == nil && derr != nil {
+			err = derr
+		}
+	}()
+
+	if _, err := tmp.WriteModel(ctx, dsl); err != nil {
+		return nil, err
+	}
+	keys := make([]client.ClientTupleKey, 0, len(tuples)+len(test.Tuples))
+	for _, t := range append(tuples[:len(tuples):len(tuples)], test.Tuples...) {
+		keys = append(keys, t.key())
+	}
+	if err := tmp.Grant(ctx, keys...); err != nil {
+		return nil, err
+	}
+
+	var results []AssertionResult
+	for _, chk := range test.Check {
+		var opts []QueryOption
+		if chk.Context != nil {
+			opts = append(opts, WithContext(chk.Context))
+		}
+		for _, t := range chk.ContextualTuples {
+			opts = append(opts, WithContextualTuples(t.key()))
+		}
+		for _, rel := range sortedKeys(chk.Assertions) {
+			r := AssertionResult{
+				Test:     test.Name,
+				User:     chk.User,
+				Relation: rel,
+				Object:   chk.Object,
+				Expected: chk.Assertions[rel],
+			}
+			r.Got, r.Err = tmp.Check(ctx, chk.User, rel, chk.Object, opts...)
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// withStore returns a handle on storeID for a scratch store: like Store,
+// but writes are always sent, since model tests need their tuples stored.
+func (c *Client) withStore(storeID string) *Client {
+	h := c.Store(storeID)
+	h.dryRun = false
+	return h
+}
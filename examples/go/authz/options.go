@@ -0,0 +1,90 @@
+package authz
+
+import (
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// MaxContextualTuples is the number of contextual tuples the OpenFGA server
+// accepts on a single request. Larger requests are rejected by the server.
+const MaxContextualTuples = 20
+
+// QueryOption configures a single query such as Check.
+type QueryOption func(*queryParams)
+
+type queryParams struct {
+	contextualTuples []client.ClientContextualTupleKey
+	context          map[string]interface{}
+	consistency      *openfga.ConsistencyPreference
+	modelID          string
+	// refresh skips the Check cache lookup but still stores the result.
+	refresh bool
+}
+
+func newQueryParams(opts []QueryOption) queryParams {
+	var p queryParams
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// WithContextualTuples attaches tuples that are considered for this request
+// only and are never persisted. Use them for request-time facts such as the
+// caller's network. The server accepts at most MaxContextualTuples; passing
+// none is identical to a plain query.
+func WithContextualTuples(tuples ...client.ClientContextualTupleKey) QueryOption {
+	return func(p *queryParams) {
+		p.contextualTuples = append(p.contextualTuples, tuples...)
+	}
+}
+
+// WithContext supplies the values that conditions in the model are evaluated
+// against, e.g. {"current_time": "2024-06-01T12:00:00Z"}. Keys set by later
+// options overwrite earlier ones.
+func WithContext(values map[string]interface{}) QueryOption {
+	return func(p *queryParams) {
+		if p.context == nil {
+			p.context = make(map[string]interface{}, len(values))
+		}
+		for k, v := range values {
+			p.context[k] = v
+		}
+	}
+}
+
+// WithConsistency selects the query's consistency mode. The server
+// defaults to openfga.CONSISTENCYPREFERENCE_MINIMIZE_LATENCY, which may
+// answer from its cache and so miss a tuple written moments ago.
+// openfga.CONSISTENCYPREFERENCE_HIGHER_CONSISTENCY reads the database
+// directly, for read-after-write checks, at the cost of higher latency and
+// database load; it also bypasses the client's Check cache.
+func WithConsistency(mode openfga.ConsistencyPreference) QueryOption {
+	return func(p *queryParams) {
+		p.consistency = &mode
+	}
+}
+
+// WithModel runs the query against the given authorization model instead
+// of the client's pinned one, e.g. to reproduce a past decision with the
+// model that was live at the time. The model must exist in the store;
+// otherwise the query fails with ErrModelNotFound. The client stays pinned
+// to its own model.
+func WithModel(id string) QueryOption {
+	return func(p *queryParams) {
+		p.modelID = id
+	}
+}
+
+// strong reports whether the query asked for higher consistency.
+func (p queryParams) strong() bool {
+	return p.consistency != nil && *p.consistency == openfga.CONSISTENCYPREFERENCE_HIGHER_CONSISTENCY
+}
+
+// contextPtr returns the request context in the pointer form the SDK expects.
+func (p queryParams) contextPtr() *map[string]interface{} {
+	if p.context == nil {
+		return nil
+	}
+	return &p.context
+}
@@ -0,0 +1,52 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+// ErrUnreachable is matched by Ping errors when the server could not be
+// reached or did not answer: connection failures, timeouts and gateway
+// errors.
+var ErrUnreachable = errors.New("authz: server unreachable")
+
+// Ping confirms the server is reachable and accepts the client's
+// credentials with the cheapest authenticated call: reading the latest model
+// of the client's store, which credentials scoped to that store may do, or
+// listing a single store when no StoreID is set. The call is made once,
+// without retries, so that a probe fails fast; give ctx a short deadline
+// for use as a readiness probe. Failures match ErrUnreachable or
+// ErrUnauthorized where they can be told apart; any other error means the
+// server answered but the call failed.
+func (c *Client) Ping(ctx context.Context) error {
+	pageSize := int32(1)
+	err := c.callAttempts(ctx, "Ping", 1, func(ctx context.Context) error {
+		if c.StoreID != "" {
+			_, err := c.fga.ReadLatestAuthorizationModel(ctx, client.ClientReadLatestAuthorizationModelOptions{StoreId: &c.StoreID})
+			return err
+		}
+		_, err := c.fga.ListStores(ctx, client.ClientListStoresOptions{PageSize: &pageSize})
+		return err
+	})
+	if err == nil {
+		return nil
+	}
+	var sc statusCoder
+	switch {
+	case errors.Is(err, ErrClosed):
+		return err
+	case errors.Is(err, ErrUnauthorized):
+		return fmt.Errorf("ping: %w", err)
+	case errors.As(err, &sc):
+		switch sc.ResponseStatusCode() {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return fmt.Errorf("ping: %w: %w", ErrUnreachable, err)
+		}
+		return fmt.Errorf("ping: %w", err)
+	}
+	return fmt.Errorf("ping: %w: %w", ErrUnreachable, err)
+}
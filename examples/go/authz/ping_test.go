@@ -0,0 +1,72 @@
+package authz_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+)
+
+func TestPing(t *testing.T) {
+	const storeID = "01HVMMBCMGZNT3SED4Z17ECXCA"
+	tests := []struct {
+		name    string
+		storeID string
+		// status is the answer to a read of the store's models; listing
+		// stores is always forbidden, as with store-scoped credentials.
+		status  int
+		wantErr error
+	}{
+		{"store ok", storeID, http.StatusOK, nil},
+		{"store unauthorized", storeID, http.StatusUnauthorized, authz.ErrUnauthorized},
+		{"store forbidden", storeID, http.StatusForbidden, authz.ErrUnauthorized},
+		{"store unavailable", storeID, http.StatusServiceUnavailable, authz.ErrUnreachable},
+		{"no store", "", http.StatusOK, authz.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/stores":
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"code":"forbidden","message":"store-scoped credentials"}`))
+				case r.Method == http.MethodGet && r.URL.Path == "/stores/"+storeID+"/authorization-models":
+					w.WriteHeader(tt.status)
+					if tt.status == http.StatusOK {
+						w.Write([]byte(`{"authorization_models":[]}`))
+					} else {
+						w.Write([]byte(`{"code":"error","message":"ping"}`))
+					}
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := authz.New(authz.Config{
+				APIURL:  srv.URL,
+				StoreID: tt.storeID,
+				Retry:   authz.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			err = c.Ping(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ping: %v, want %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != 1 {
+				t.Errorf("Ping sent %d requests, want 1", got)
+			}
+		})
+	}
+}
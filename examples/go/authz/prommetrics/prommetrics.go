@@ -0,0 +1,59 @@
+// Package prommetrics exports authz client metrics to Prometheus:
+//
+//	m, err := prommetrics.New(prometheus.DefaultRegisterer)
+//	fga, err := authz.New(authz.Config{APIURL: url, Metrics: m})
+//
+// Every series is labelled by operation only, the SDK method name, so
+// cardinality stays fixed regardless of traffic.
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements authz.Metrics with a request counter, an error counter
+// and a latency histogram.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+var _ authz.Metrics = (*Metrics)(nil)
+
+// New creates the collectors and registers them with reg.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fga_client_requests_total",
+			Help: "OpenFGA API calls, counted once per call regardless of retries.",
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fga_client_request_errors_total",
+			Help: "OpenFGA API calls that failed after all retries.",
+		}, []string{"operation"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "fga_client_request_duration_seconds",
+			Help:    "OpenFGA API call latency, including retries and backoff.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+	}
+	for _, c := range []prometheus.Collector{m.requests, m.errors, m.latency} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveCall implements authz.Metrics.
+func (m *Metrics) ObserveCall(op string, dur time.Duration, err error) {
+	m.requests.WithLabelValues(op).Inc()
+	m.latency.WithLabelValues(op).Observe(dur.Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(op).Inc()
+	}
+}
@@ -0,0 +1,81 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+// GrantPublic grants relation on object to everyone by writing a wildcard
+// tuple such as user:* for a relation defined as [user:*]. The wildcard
+// type is taken from the active model and must be unambiguous: the relation
+// must allow exactly one unconditional wildcard.
+func (c *Client) GrantPublic(ctx context.Context, relation, object string) error {
+	objType, _, err := splitObject(object)
+	if err != nil {
+		return err
+	}
+	m, err := c.Model(ctx)
+	if err != nil {
+		return err
+	}
+	var types []string
+	for _, ref := range m.idx.directTypes(objType, relation) {
+		if ref.Wildcard != nil && ref.Condition == nil {
+			types = append(types, ref.Type)
+		}
+	}
+	switch len(types) {
+	case 0:
+		return fmt.Errorf("grant public: %s#%s does not allow a wildcard", objType, relation)
+	case 1:
+	default:
+		return fmt.Errorf("grant public: %s#%s allows several wildcards (%s); write the tuple explicitly",
+			objType, relation, strings.Join(types, ", "))
+	}
+	return c.Grant(ctx, client.ClientTupleKey{User: types[0] + ":*", Relation: relation, Object: object})
+}
+
+// IsPublic reports whether relation on object is granted to everyone of
+// some type through a wildcard, as opposed to named users. It checks the
+// wildcard of every type that may be assigned one anywhere in the active
+// model, so access that reaches object indirectly (e.g. through a computed
+// relation or a parent) is found too.
+func (c *Client) IsPublic(ctx context.Context, relation, object string, opts ...QueryOption) (bool, error) {
+	m, err := c.ActiveModel(ctx)
+	if err != nil {
+		return false, err
+	}
+	wildcards := map[string]bool{}
+	for _, td := range m.TypeDefinitions {
+		if td.Metadata == nil || td.Metadata.Relations == nil {
+			continue
+		}
+		for _, md := range *td.Metadata.Relations {
+			if md.DirectlyRelatedUserTypes == nil {
+				continue
+			}
+			for _, ref := range *md.DirectlyRelatedUserTypes {
+				if ref.Wildcard != nil {
+					wildcards[ref.Type] = true
+				}
+			}
+		}
+	}
+	types := make([]string, 0, len(wildcards))
+	for typ := range wildcards {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	for _, typ := range types {
+		ok, err := c.Check(ctx, typ+":*", relation, object, opts...)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,114 @@
+package authz
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+// Redactor transforms a user or object, such as "user:anne" or
+// "group:eng#member", before it appears in a log record, an error message
+// or a span. It must be safe for concurrent use.
+type Redactor func(s string) string
+
+// HashRedactor returns a Redactor that keeps the type and any userset
+// relation but replaces the ID with the first 8 hex digits of its
+// HMAC-SHA256 under key, so "user:anne" becomes "user:3f9a0c12…". The same
+// ID always maps to the same digest, so records about one user can still be
+// correlated. Wildcards are kept as is, and a malformed value without a
+// type is hashed whole. Use a secret key: with a known key a low-entropy ID
+// such as a phone number is recovered by hashing every candidate.
+func HashRedactor(key []byte) Redactor {
+	hash := func(id string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(id))
+		return hex.EncodeToString(mac.Sum(nil))[:8] + "…"
+	}
+	return func(s string) string {
+		typ, id, ok := strings.Cut(s, ":")
+		if !ok {
+			return hash(s)
+		}
+		id, relation, hasRelation := strings.Cut(id, "#")
+		if id == "" || id == "*" {
+			return s
+		}
+		out := typ + ":" + hash(id)
+		if hasRelation {
+			out += "#" + relation
+		}
+		return out
+	}
+}
+
+// redactValue returns s as the client's Redactor presents it.
+func (c *Client) redactValue(s string) string {
+	if c.redact == nil || s == "" {
+		return s
+	}
+	return c.redact(s)
+}
+
+// redactErr returns err with every occurrence of values in its message
+// replaced by its redacted form. The original error stays in the chain, so
+// errors.Is and errors.As behave as for err itself; only the message
+// changes. err is returned unchanged when no value occurs in it.
+func (c *Client) redactErr(err error, values ...string) error {
+	if err == nil || c.redact == nil {
+		return err
+	}
+	// Longer values first, so that "doc:1#viewer" is not redacted as
+	// "doc:1" followed by "#viewer".
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	pairs := make([]string, 0, 2*len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		if r := c.redact(v); r != v {
+			pairs = append(pairs, v, r)
+		}
+	}
+	msg := err.Error()
+	redacted := strings.NewReplacer(pairs...).Replace(msg)
+	if redacted == msg {
+		return err
+	}
+	return &redactedError{err: err, msg: redacted}
+}
+
+// redactedError is an error whose message has been redacted.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// writeValues returns the users and objects of the tuples in req.
+func writeValues(req client.ClientWriteRequest) []string {
+	values := make([]string, 0, 2*(len(req.Writes)+len(req.Deletes)))
+	for _, tk := range req.Writes {
+		values = append(values, tk.User, tk.Object)
+	}
+	for _, tk := range req.Deletes {
+		values = append(values, tk.User, tk.Object)
+	}
+	return values
+}
+
+// queryValues returns values followed by the users and objects of the
+// contextual tuples sent with a query.
+func queryValues(tuples []client.ClientContextualTupleKey, values ...string) []string {
+	for _, tk := range tuples {
+		values = append(values, tk.User, tk.Object)
+	}
+	return values
+}
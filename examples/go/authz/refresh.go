@@ -0,0 +1,81 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ModelWatch configures WatchModel.
+type ModelWatch struct {
+	// Interval is how often the latest model is polled. Defaults to 30
+	// seconds.
+	Interval time.Duration
+	// ClearCache evicts the store's cached Check results for other models
+	// when the pinned model changes. Results are cached per model, so stale
+	// answers are never served either way; clearing only frees the space
+	// sooner.
+	ClearCache bool
+}
+
+// DefaultModelWatchInterval is used when ModelWatch.Interval is unset.
+const DefaultModelWatchInterval = 30 * time.Second
+
+// WatchModel keeps the client pinned to the store's latest model, so that a
+// newly written model takes effect without a restart. It polls
+// ReadLatestAuthorizationModel every w.Interval and re-pins the client when
+// the latest model changes; see RefreshModel. Each query still resolves the
+// model once, so it runs against a single model even if a swap happens
+// while it is in flight, and ModelID reports the model currently in use.
+//
+// WatchModel blocks until ctx is done or the client is closed, returning
+// ctx.Err() or ErrClosed; run it in its own goroutine. A failed poll is
+// logged and retried on the next tick. Do not change StoreID while
+// watching.
+func (c *Client) WatchModel(ctx context.Context, w ModelWatch) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultModelWatchInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+		if _, err := c.refreshModel(ctx, w.ClearCache); err != nil {
+			if errors.Is(err, ErrClosed) || ctx.Err() != nil {
+				return err
+			}
+			c.logger().Warn("could not refresh authorization model", "operation", "WatchModel", "error", err)
+		}
+	}
+}
+
+// RefreshModel pins the client to the store's latest model and reports
+// whether the pinned model changed. A store without a model leaves the pin
+// unchanged.
+func (c *Client) RefreshModel(ctx context.Context) (bool, error) {
+	return c.refreshModel(ctx, false)
+}
+
+func (c *Client) refreshModel(ctx context.Context, clearCache bool) (bool, error) {
+	id, err := c.LatestModelID(ctx)
+	if err != nil || id == "" {
+		return false, err
+	}
+	c.mu.Lock()
+	previous := c.modelID
+	c.modelID = id
+	c.mu.Unlock()
+	if previous == id {
+		return false, nil
+	}
+	if clearCache && c.cache != nil {
+		c.cache.retainModel(c.StoreID, id)
+	}
+	c.logger().Info("switched to latest authorization model", "operation", "RefreshModel", "previous_model_id", previous)
+	return true, nil
+}
@@ -0,0 +1,167 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// RenderDSL renders type definitions in the OpenFGA DSL, the inverse of
+// ParseDSL. Types keep their order; relations, whose order the API does not
+// preserve, are rendered alphabetically. Conditions are not rendered; use
+// RenderModel for a model that declares them.
+func RenderDSL(typeDefs []openfga.TypeDefinition, schemaVersion string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "model\n  schema %s\n", schemaVersion)
+	for _, td := range typeDefs {
+		fmt.Fprintf(&b, "\ntype %s\n", td.Type)
+		names := relationNames(td)
+		if len(names) == 0 {
+			continue
+		}
+		b.WriteString("  relations\n")
+		idx := indexTypeDefs([]openfga.TypeDefinition{td})
+		for _, name := range names {
+			expr, err := renderUserset((*td.Relations)[name], idx.directTypes(td.Type, name), false)
+			if err != nil {
+				return "", fmt.Errorf("type %q relation %q: %w", td.Type, name, err)
+			}
+			fmt.Fprintf(&b, "    define %s: %s\n", name, expr)
+		}
+	}
+	return b.String(), nil
+}
+
+// RenderModel renders a whole model in the OpenFGA DSL, the inverse of
+// ModelFromDSL: its type definitions as RenderDSL does, followed by its
+// conditions ordered by name. Parsing the output with ModelFromDSL yields
+// the same model.
+func RenderModel(model client.ClientWriteAuthorizationModelRequest) (string, error) {
+	out, err := RenderDSL(model.TypeDefinitions, model.SchemaVersion)
+	if err != nil || model.Conditions == nil {
+		return out, err
+	}
+	var b strings.Builder
+	b.WriteString(out)
+	for _, name := range sortedKeys(*model.Conditions) {
+		s, err := renderCondition(name, (*model.Conditions)[name])
+		if err != nil {
+			return "", fmt.Errorf("condition %q: %w", name, err)
+		}
+		b.WriteString("\n" + s)
+	}
+	return b.String(), nil
+}
+
+// renderCondition renders a condition, parameters in alphabetical order and
+// each line of its expression indented.
+func renderCondition(name string, cond openfga.Condition) (string, error) {
+	if cond.Parameters == nil || len(*cond.Parameters) == 0 {
+		return "", fmt.Errorf("no parameters")
+	}
+	if strings.TrimSpace(cond.Expression) == "" {
+		return "", fmt.Errorf("empty expression")
+	}
+	params := make([]string, 0, len(*cond.Parameters))
+	for _, param := range sortedKeys(*cond.Parameters) {
+		typ, err := renderConditionType((*cond.Parameters)[param])
+		if err != nil {
+			return "", fmt.Errorf("parameter %q: %w", param, err)
+		}
+		params = append(params, param+": "+typ)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "condition %s(%s) {\n", name, strings.Join(params, ", "))
+	for _, line := range strings.Split(cond.Expression, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// renderConditionType renders a parameter type, e.g. "list<string>".
+func renderConditionType(ref openfga.ConditionParamTypeRef) (string, error) {
+	var base string
+	for name, tn := range conditionTypes {
+		if tn == ref.TypeName {
+			base = name
+			break
+		}
+	}
+	if base == "" {
+		return "", fmt.Errorf("unsupported type %q", ref.TypeName)
+	}
+	if ref.GenericTypes == nil || len(*ref.GenericTypes) == 0 {
+		return base, nil
+	}
+	if len(*ref.GenericTypes) != 1 {
+		return "", fmt.Errorf("type %s: %d type arguments", base, len(*ref.GenericTypes))
+	}
+	elem, err := renderConditionType((*ref.GenericTypes)[0])
+	if err != nil {
+		return "", err
+	}
+	return base + "<" + elem + ">", nil
+}
+
+// renderUserset renders a rewrite. nested wraps compound rewrites in
+// parentheses so operator precedence survives a round trip.
+func renderUserset(us openfga.Userset, direct []openfga.RelationReference, nested bool) (string, error) {
+	var (
+		parts []string
+		op    string
+	)
+	switch {
+	case us.This != nil:
+		if len(direct) == 0 {
+			return "", fmt.Errorf("direct assignment without type restrictions")
+		}
+		refs := make([]string, len(direct))
+		for i, ref := range direct {
+			refs[i] = refKey(ref)
+		}
+		return "[" + strings.Join(refs, ", ") + "]", nil
+	case us.ComputedUserset != nil:
+		return us.ComputedUserset.GetRelation(), nil
+	case us.TupleToUserset != nil:
+		return us.TupleToUserset.ComputedUserset.GetRelation() + " from " + us.TupleToUserset.Tupleset.GetRelation(), nil
+	case us.Union != nil:
+		op = " or "
+		for _, child := range us.Union.Child {
+			s, err := renderUserset(child, direct, true)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+	case us.Intersection != nil:
+		op = " and "
+		for _, child := range us.Intersection.Child {
+			s, err := renderUserset(child, direct, true)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+	case us.Difference != nil:
+		op = " but not "
+		for _, child := range []openfga.Userset{us.Difference.Base, us.Difference.Subtract} {
+			s, err := renderUserset(child, direct, true)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+	default:
+		return "", fmt.Errorf("empty rewrite")
+	}
+	s := strings.Join(parts, op)
+	if nested {
+		s = "(" + s + ")"
+	}
+	return s, nil
+}
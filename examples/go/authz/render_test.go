@@ -0,0 +1,51 @@
+package authz
+
+import (
+	"os"
+	"testing"
+)
+
+// exampleModels are the models shipped with the repository.
+var exampleModels = []struct {
+	name string
+	path string
+}{
+	{"sample", "../model.fga"},
+	{"api", "../../../models/api/model.fga"},
+	{"saas", "../../../models/saas/model.fga"},
+	{"rbac", "../../../models/rbac/model.fga"},
+	{"abac", "../../../models/abac/model.fga"},
+}
+
+func TestRenderDSLRoundTrip(t *testing.T) {
+	for _, tc := range exampleModels {
+		t.Run(tc.name, func(t *testing.T) {
+			dsl, err := os.ReadFile(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			model, err := ModelFromDSL(string(dsl), "")
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			rendered, err := RenderModel(model)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			reparsed, err := ModelFromDSL(rendered, "")
+			if err != nil {
+				t.Fatalf("parse rendered model: %v\n%s", err, rendered)
+			}
+			if !modelsEqual(model, reparsed) {
+				t.Errorf("model changed in round trip; rendered:\n%s", rendered)
+			}
+			again, err := RenderModel(reparsed)
+			if err != nil {
+				t.Fatalf("render again: %v", err)
+			}
+			if again != rendered {
+				t.Errorf("rendering is not stable:\n%s\nthen:\n%s", rendered, again)
+			}
+		})
+	}
+}
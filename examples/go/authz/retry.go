@@ -0,0 +1,169 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed SDK calls are retried. The zero value
+// disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt; it doubles on
+	// each subsequent attempt. Defaults to 100ms.
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff. Defaults to 5s.
+	MaxDelay time.Duration
+}
+
+// backoff returns the full-jitter delay before attempt n (1-based retry).
+func (p RetryPolicy) backoff(n int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 5 * time.Second
+	}
+	d := base << (n - 1)
+	if d <= 0 || d > maxDelay {
+		d = maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// call runs fn under the client's retry policy. Only rate limiting, server
+// unavailability and transient network errors are retried; everything else
+// fails on the first attempt. A tuple Write is retried only when the server
+// cannot have applied it; see retryable. No retry is started that would
+// outlive the deadline of ctx. The whole call, retries included, is
+// reported to the client's Metrics as op.
+//
+// If ctx has no deadline the client's DefaultTimeout applies; exceeding it
+// yields an error matching ErrTimeout. Other failures are classified by
+// classifyError. With Config.Bootstrap the first call provisions the
+// store and model before running fn; see ensureInit.
+func (c *Client) call(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	return c.callAttempts(ctx, op, c.retry.MaxAttempts, fn)
+}
+
+// callAttempts is call with at most attempts tries of fn, whatever the
+// client's retry policy allows.
+func (c *Client) callAttempts(ctx context.Context, op string, attempts int, fn func(ctx context.Context) error) (err error) {
+	if c.closed.Load() {
+		return ErrClosed
+	}
+	if err := c.ensureInit(ctx); err != nil {
+		return err
+	}
+	defer func() { err = classifyError(err) }()
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		parent := ctx
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		defer func() {
+			if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("%w: %s exceeded %s: %w", ErrTimeout, op, c.timeout, err)
+			}
+		}()
+	}
+
+	start := time.Now()
+	defer func() {
+		dur := time.Since(start)
+		c.metrics.ObserveCall(op, dur, err)
+		if c.log.Enabled(ctx, slog.LevelDebug) {
+			c.logger().Debug("fga call", "operation", op, "duration", dur, "error", err)
+		}
+	}()
+
+	if attempts < 1 {
+		attempts = 1
+	}
+	for n := 1; ; n++ {
+		err = fn(ctx)
+		if err == nil || n >= attempts || ctx.Err() != nil || !retryable(op, err) {
+			return err
+		}
+		delay := c.retry.backoff(n)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+		c.logger().Warn("retrying fga call", "operation", op, "attempt", n, "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// ErrTimeout is matched by errors from calls cut short by
+// Config.DefaultTimeout.
+var ErrTimeout = errors.New("authz: call timed out")
+
+// statusCoder is implemented by the SDK's API error types.
+type statusCoder interface {
+	ResponseStatusCode() int
+}
+
+// noRetry marks an error that must not be retried, e.g. because part of
+// the result has already been delivered.
+type noRetry struct{ error }
+
+func (e noRetry) Unwrap() error { return e.error }
+
+// retryable reports whether call may retry op after err. A Write whose
+// response was lost, e.g. to a 500, 502 or 504 or a reset connection, may
+// have committed, and retrying it would then fail on the tuples it already
+// stored, reporting a successful write as failed. It is retried only when it
+// was refused before reaching the datastore: rate limited, rejected as
+// unavailable, or never connected.
+func retryable(op string, err error) bool {
+	if op != "Write" {
+		return isRetryable(err)
+	}
+	if errors.As(err, new(noRetry)) {
+		return false
+	}
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		code := sc.ResponseStatusCode()
+		return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func isRetryable(err error) bool {
+	if errors.As(err, new(noRetry)) {
+		return false
+	}
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		switch sc.ResponseStatusCode() {
+		case http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,56 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+// writeStub fails every Write with err and counts the attempts.
+type writeStub struct {
+	FGA
+	err      error
+	attempts int
+}
+
+func (s *writeStub) Write(ctx context.Context, body client.ClientWriteRequest, opts client.ClientWriteOptions) (*client.ClientWriteResponse, error) {
+	s.attempts++
+	return nil, s.err
+}
+
+func TestWriteRetries(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"rate limited", &httpError{code: http.StatusTooManyRequests}, 3},
+		{"unavailable", &httpError{code: http.StatusServiceUnavailable}, 3},
+		{"connection refused", syscall.ECONNREFUSED, 3},
+		{"internal error", &httpError{code: http.StatusInternalServerError}, 1},
+		{"bad gateway", &httpError{code: http.StatusBadGateway}, 1},
+		{"gateway timeout", &httpError{code: http.StatusGatewayTimeout}, 1},
+		{"connection reset", syscall.ECONNRESET, 1},
+		{"invalid", &httpError{code: http.StatusBadRequest}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &writeStub{err: tt.err}
+			c := NewWithFGA(stub, Config{
+				StoreID: "01HVMMBCMGZNT3SED4Z17ECXCA",
+				Retry:   RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			})
+			err := c.Grant(context.Background(), client.ClientTupleKey{User: "user:anne", Relation: "viewer", Object: "document:1"})
+			if err == nil {
+				t.Fatal("Grant succeeded, want error")
+			}
+			if stub.attempts != tt.want {
+				t.Errorf("Write attempted %d times, want %d", stub.attempts, tt.want)
+			}
+		})
+	}
+}
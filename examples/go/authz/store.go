@@ -0,0 +1,255 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// Store returns a handle on another store that shares c's connection,
+// settings, Check cache and metrics, for serving several tenants' stores
+// over one connection. The handle starts with no model pinned and pins the
+// store's latest model on first use, unless one is pinned with PinModel.
+// Changes to the handle's store or model do not affect c. Closing any
+// handle closes the shared connection for all of them.
+func (c *Client) Store(storeID string) *Client {
+	return &Client{
+		fga:              c.fga,
+		checkConcurrency: c.checkConcurrency,
+		listLimit:        c.listLimit,
+		retry:            c.retry,
+		timeout:          c.timeout,
+		validate:         c.validate,
+		dryRun:           c.dryRun,
+		cache:            c.cache,
+		expiry:           c.expiry,
+		tracer:           c.tracer,
+		metrics:          c.metrics,
+		log:              c.log,
+		redact:           c.redact,
+		closed:           c.closed,
+		noBatchCheck:     c.noBatchCheck,
+		StoreID:          storeID,
+	}
+}
+
+// setStore makes storeID the client's active store. A pinned model belongs
+// to the store it was pinned on, so switching stores drops the pin and the
+// models indexed for the old store; the next query resolves the new store's
+// latest model.
+func (c *Client) setStore(storeID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.StoreID != storeID {
+		c.modelID = ""
+		c.models = nil
+	}
+	c.StoreID = storeID
+}
+
+// CreateStore creates a new store and makes it the client's active store,
+// dropping any model pinned on the previous one.
+//
+// OpenFGA has no idempotency key for store creation, so a request whose
+// response is lost, e.g. to a network timeout, may have created the store
+// anyway, and blindly retrying it would create a second one. A store
+// carries nothing but its name and creation time to recognize it by, so
+// only after such a failure, before retrying and once more if the last
+// attempt fails that way, CreateStore lists the stores and adopts one
+// called name that was created since the call began, instead of creating
+// another. To tolerate clock skew between client and server, "since" is
+// taken as storeClockSkew before the call began. A successful first
+// attempt costs no listing; the price is that a store of the same name
+// created by another process in that window is adopted too.
+// GetOrCreateStore is the call for sharing one store between processes.
+func (c *Client) CreateStore(ctx context.Context, name string) (string, error) {
+	return c.createStore(ctx, name, time.Now().Add(-storeClockSkew))
+}
+
+// storeClockSkew is how far the server's clock may lag the client's for
+// CreateStore to still recognize a store it created.
+const storeClockSkew = 10 * time.Second
+
+// createStore creates a store called name, reconciling after failures
+// that may have created it anyway by adopting a store called name created
+// at or after since.
+func (c *Client) createStore(ctx context.Context, name string, since time.Time) (string, error) {
+	var (
+		id       string
+		attempts int
+	)
+	err := c.call(ctx, "CreateStore", func(ctx context.Context) error {
+		if attempts++; attempts > 1 {
+			found, err := c.createdStore(ctx, name, since)
+			if err != nil {
+				return noRetry{err}
+			}
+			if found != "" {
+				id = found
+				return nil
+			}
+		}
+		resp, err := c.fga.CreateStore(ctx, client.ClientCreateStoreRequest{
+			Name: name,
+		})
+		if err != nil {
+			return err
+		}
+		id = resp.Id
+		return nil
+	})
+	if err != nil && ctx.Err() == nil && (errors.Is(err, ErrTimeout) || isRetryable(err)) {
+		if found, rerr := c.createdStore(ctx, name, since); rerr == nil && found != "" {
+			id, err = found, nil
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("create store: %w", err)
+	}
+	c.setStore(id)
+	return id, nil
+}
+
+// createdStore returns the oldest store called name created at or after
+// since, i.e. one a failed CreateStore attempt created after all.
+func (c *Client) createdStore(ctx context.Context, name string, since time.Time) (string, error) {
+	stores, err := c.ListStores(ctx)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	for _, s := range stores {
+		if s.Name == name && !s.CreatedAt.Before(since) && (id == "" || s.Id < id) {
+			id = s.Id
+		}
+	}
+	if id != "" {
+		c.logger().Warn("store creation failed but the store exists; using it",
+			"operation", "CreateStore", "store_id", id, "name", name)
+	}
+	return id, nil
+}
+
+// GetOrCreateStore makes the store called name the active store, creating
+// it only if no store with that name exists, so repeated runs do not leave
+// orphan stores behind. Switching stores drops a pinned model.
+//
+// Store names are not unique in OpenFGA. When several stores share the name
+// (e.g. two processes raced to create it) the one with the lowest ID, which
+// is the oldest, wins; a store this call created and lost the race with is
+// deleted again.
+func (c *Client) GetOrCreateStore(ctx context.Context, name string) (string, error) {
+	if id, ok, err := c.lowestStoreID(ctx, name); err != nil || ok {
+		if ok {
+			c.setStore(id)
+		}
+		return id, err
+	}
+
+	// No store is called name yet, so any store of that name found when
+	// reconciling a failed creation is ours, or one to share.
+	created, err := c.createStore(ctx, name, time.Time{})
+	if err != nil {
+		return "", err
+	}
+	id, ok, err := c.lowestStoreID(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if ok && id != created {
+		// Another process won; our store is unused. Failing to delete it
+		// leaves an empty store behind, which is harmless.
+		if err := c.DeleteStore(ctx, created); err != nil {
+			c.logger().Warn("could not delete store that lost creation race",
+				"operation", "GetOrCreateStore", "duplicate_store_id", created, "error", err)
+		}
+		c.setStore(id)
+		return id, nil
+	}
+	return created, nil
+}
+
+// lowestStoreID returns the lowest ID among stores called name.
+func (c *Client) lowestStoreID(ctx context.Context, name string) (string, bool, error) {
+	stores, err := c.ListStores(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	var id string
+	for _, s := range stores {
+		if s.Name == name && (id == "" || s.Id < id) {
+			id = s.Id
+		}
+	}
+	return id, id != "", nil
+}
+
+// ListStores returns every store visible to the client, following
+// continuation tokens across pages.
+func (c *Client) ListStores(ctx context.Context) ([]openfga.Store, error) {
+	var (
+		stores []openfga.Store
+		token  string
+	)
+	for {
+		var resp *client.ClientListStoresResponse
+		err := c.call(ctx, "ListStores", func(ctx context.Context) (err error) {
+			resp, err = c.fga.ListStores(ctx, client.ClientListStoresOptions{ContinuationToken: optional(token)})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list stores: %w", err)
+		}
+		stores = append(stores, resp.Stores...)
+		token = resp.ContinuationToken
+		if token == "" {
+			return stores, nil
+		}
+	}
+}
+
+// DeleteStore deletes the store with the given ID. Deleting the client's
+// active store clears StoreID and the pinned model.
+func (c *Client) DeleteStore(ctx context.Context, storeID string) error {
+	err := c.call(ctx, "DeleteStore", func(ctx context.Context) error {
+		_, err := c.fga.DeleteStore(ctx, client.ClientDeleteStoreOptions{StoreId: &storeID})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("delete store %s: %w", storeID, err)
+	}
+	if c.StoreID == storeID {
+		c.setStore("")
+	}
+	return nil
+}
+
+// DeleteStoresByPrefix deletes every store whose name starts with prefix and
+// returns how many were removed. It is meant for tearing down throwaway
+// stores created by test suites, e.g. DeleteStoresByPrefix(ctx, "test-").
+// An empty prefix is rejected rather than deleting every store.
+func (c *Client) DeleteStoresByPrefix(ctx context.Context, prefix string) (int, error) {
+	if prefix == "" {
+		return 0, fmt.Errorf("authz: refusing to delete stores with an empty prefix")
+	}
+	stores, err := c.ListStores(ctx)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, s := range stores {
+		if !strings.HasPrefix(s.Name, prefix) {
+			continue
+		}
+		if err := c.DeleteStore(ctx, s.Id); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,166 @@
+package authz_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+	"github.com/bogdanticu88/openfga-examples/authz/fake"
+	"github.com/openfga/go-sdk/client"
+)
+
+const storeTestModel = `model
+  schema 1.1
+
+type user
+
+type document
+  relations
+    define viewer: [user]
+`
+
+// TestSwitchStoreUnpinsModel checks that a model pinned on one store is not
+// sent with queries against the next.
+func TestSwitchStoreUnpinsModel(t *testing.T) {
+	ctx := context.Background()
+	anne := client.ClientTupleKey{User: "user:anne", Relation: "viewer", Object: "document:1"}
+	switches := []struct {
+		name string
+		do   func(c *authz.Client) error
+	}{
+		{"CreateStore", func(c *authz.Client) error {
+			_, err := c.CreateStore(ctx, "second")
+			return err
+		}},
+		{"GetOrCreateStore", func(c *authz.Client) error {
+			_, err := c.GetOrCreateStore(ctx, "second")
+			return err
+		}},
+	}
+	for _, tt := range switches {
+		t.Run(tt.name, func(t *testing.T) {
+			c := authz.NewWithFGA(fake.New(), authz.Config{})
+			if _, err := c.CreateStore(ctx, "first"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.WriteModel(ctx, storeTestModel); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Check(ctx, anne.User, anne.Relation, anne.Object); err != nil {
+				t.Fatal(err)
+			}
+			first := c.ModelID()
+
+			if err := tt.do(c); err != nil {
+				t.Fatal(err)
+			}
+			if id := c.ModelID(); id != "" {
+				t.Fatalf("ModelID() = %q after switching stores, want it unpinned", id)
+			}
+			if _, err := c.WriteModel(ctx, storeTestModel); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Grant(ctx, anne); err != nil {
+				t.Fatal(err)
+			}
+			allowed, err := c.Check(ctx, anne.User, anne.Relation, anne.Object)
+			if err != nil {
+				t.Fatalf("Check on the new store: %v", err)
+			}
+			if !allowed {
+				t.Error("Check on the new store denied a granted tuple")
+			}
+			if c.ModelID() == first {
+				t.Errorf("ModelID() = %q, still the first store's model", first)
+			}
+		})
+	}
+}
+
+// lossyStores is a fake whose first lost CreateStore calls create the store
+// but fail as if the response was lost. Stores listed in aged are reported
+// as created an hour ago.
+type lossyStores struct {
+	*fake.Client
+	lost  int
+	aged  map[string]bool
+	lists int
+}
+
+func (f *lossyStores) CreateStore(ctx context.Context, body client.ClientCreateStoreRequest) (*client.ClientCreateStoreResponse, error) {
+	resp, err := f.Client.CreateStore(ctx, body)
+	if err == nil && f.lost > 0 {
+		f.lost--
+		return nil, syscall.ECONNRESET
+	}
+	return resp, err
+}
+
+func (f *lossyStores) ListStores(ctx context.Context, opts client.ClientListStoresOptions) (*client.ClientListStoresResponse, error) {
+	f.lists++
+	resp, err := f.Client.ListStores(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range resp.Stores {
+		if f.aged[s.Id] {
+			resp.Stores[i].CreatedAt = s.CreatedAt.Add(-time.Hour)
+		}
+	}
+	return resp, nil
+}
+
+func TestCreateStoreReconcile(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		lost      int
+		oldStore  bool
+		wantLists int
+		// wantStores is the number of stores called "tenant" afterwards.
+		wantStores int
+	}{
+		{"success lists nothing", 0, false, 0, 1},
+		{"lost response adopts the store", 1, false, 1, 1},
+		{"older store of the same name is not adopted", 1, true, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &lossyStores{Client: fake.New(), aged: map[string]bool{}}
+			if tt.oldStore {
+				resp, err := f.Client.CreateStore(ctx, client.ClientCreateStoreRequest{Name: "tenant"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				f.aged[resp.Id] = true
+			}
+			f.lost = tt.lost
+			c := authz.NewWithFGA(f, authz.Config{Retry: authz.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}})
+			id, err := c.CreateStore(ctx, "tenant")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.aged[id] {
+				t.Errorf("CreateStore adopted the older store %s", id)
+			}
+			if f.lists != tt.wantLists {
+				t.Errorf("CreateStore listed stores %d times, want %d", f.lists, tt.wantLists)
+			}
+			stores, err := f.Client.ListStores(ctx, client.ClientListStoresOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := 0
+			for _, s := range stores.Stores {
+				if s.Name == "tenant" {
+					n++
+				}
+			}
+			if n != tt.wantStores {
+				t.Errorf("%d stores called tenant, want %d", n, tt.wantStores)
+			}
+		})
+	}
+}
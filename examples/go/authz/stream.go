@@ -0,0 +1,214 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// ObjectStreamer is implemented by FGA implementations that support the
+// StreamedListObjects endpoint. fn is called with each object as it
+// arrives; an error from fn stops the stream and is returned.
+type ObjectStreamer interface {
+	StreamedListObjects(ctx context.Context, body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions, fn func(object string) error) error
+}
+
+// StreamListObjects sends to out each object of objType on which user has
+// relation, as the server finds them. It returns nil once every object has
+// been sent and ctx.Err() if ctx is cancelled first; out is not closed.
+//
+// ListObjects buffers the whole result and the server truncates it at its
+// list-objects result limit (1000 by default). The streamed endpoint has no
+// result limit and delivers the first objects before the rest are resolved,
+// so prefer it when a user may reach many objects; both are still bounded
+// by the server's list-objects deadline. When the FGA implementation does
+// not implement ObjectStreamer, StreamListObjects falls back to ListObjects;
+// a truncated result is then sent in full before ErrResultTruncated is
+// returned.
+//
+// A failed stream is retried only if no object has been sent yet, so out
+// never receives duplicates. An empty user defaults to the one attached to
+// ctx by WithUser.
+func (c *Client) StreamListObjects(ctx context.Context, user, relation, objType string, out chan<- string, opts ...QueryOption) (err error) {
+	user = userOrContext(ctx, user)
+	streamer, ok := c.fga.(ObjectStreamer)
+	if !ok {
+		objects, err := c.ListObjects(ctx, user, relation, objType, opts...)
+		if err != nil && !errors.Is(err, ErrResultTruncated) {
+			return err
+		}
+		for _, obj := range objects {
+			select {
+			case out <- obj:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return err
+	}
+
+	ctx, span := c.startSpan(ctx, "StreamListObjects", relationAttr(relation), objectTypeAttr(objType))
+	defer func() { endSpan(span, err) }()
+
+	p := newQueryParams(opts)
+	modelID, err := c.queryModel(ctx, p)
+	if err != nil {
+		return err
+	}
+	setSpanModel(span, modelID)
+	var sent bool
+	err = c.call(ctx, "StreamedListObjects", func(ctx context.Context) error {
+		err := streamer.StreamedListObjects(ctx, client.ClientListObjectsRequest{
+			User:             user,
+			Relation:         relation,
+			Type:             objType,
+			Context:          p.contextPtr(),
+			ContextualTuples: p.contextualTuples,
+		}, client.ClientListObjectsOptions{
+			AuthorizationModelId: modelID,
+			StoreId:              &c.StoreID,
+			Consistency:          p.consistency,
+		}, func(object string) error {
+			select {
+			case out <- object:
+				sent = true
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		err = c.redactErr(err, queryValues(p.contextualTuples, user)...)
+		if err != nil && sent {
+			return noRetry{err}
+		}
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("stream list objects: %w", err)
+	}
+	return nil
+}
+
+// listObjectsRequest builds the API request body shared by ListObjects and
+// StreamedListObjects.
+func listObjectsRequest(body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions) openfga.ListObjectsRequest {
+	return openfga.ListObjectsRequest{
+		AuthorizationModelId: opts.AuthorizationModelId,
+		Type:                 body.Type,
+		Relation:             body.Relation,
+		User:                 body.User,
+		ContextualTuples:     contextualTupleKeys(body.ContextualTuples),
+		Context:              body.Context,
+		Consistency:          opts.Consistency,
+	}
+}
+
+// StreamedListObjects calls the streaming endpoint directly, as the SDK
+// does not expose it. The server writes one JSON message per object.
+func (s sdkFGA) StreamedListObjects(ctx context.Context, body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions, fn func(object string) error) error {
+	b, err := json.Marshal(listObjectsRequest(body, opts))
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimRight(s.url, "/") + "/stores/" + url.PathEscape(deref(opts.StoreId)) + "/streamed-list-objects"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr)
+		return &httpError{code: resp.StatusCode, msg: strings.TrimSpace(apiErr.Code + " " + apiErr.Message)}
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			Result *struct {
+				Object string `json:"object"`
+			} `json:"result"`
+			Error *struct {
+				HTTPCode int    `json:"http_code"`
+				Message  string `json:"message"`
+			} `json:"error"`
+		}
+		if err := dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("decode stream: %w", err)
+		}
+		if msg.Error != nil {
+			return &httpError{code: msg.Error.HTTPCode, msg: msg.Error.Message}
+		}
+		if msg.Result != nil {
+			if err := fn(msg.Result.Object); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// httpError is an API error from a request made outside the SDK. Like the
+// SDK's error types, it exposes the status code for retry classification.
+type httpError struct {
+	code int
+	msg  string
+}
+
+func (e *httpError) Error() string {
+	if e.msg == "" {
+		return fmt.Sprintf("HTTP %d", e.code)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.code, e.msg)
+}
+
+func (e *httpError) ResponseStatusCode() int { return e.code }
+
+func (g *grpcFGA) StreamedListObjects(ctx context.Context, body client.ClientListObjectsRequest, opts client.ClientListObjectsOptions, fn func(object string) error) error {
+	var req openfgav1.StreamedListObjectsRequest
+	if err := toProto(listObjectsRequest(body, opts), &req); err != nil {
+		return err
+	}
+	req.StoreId = deref(opts.StoreId)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := g.svc.StreamedListObjects(ctx, &req)
+	if err != nil {
+		return grpcErr(err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return grpcErr(err)
+		}
+		if err := fn(resp.GetObject()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package authz
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/bogdanticu88/openfga-examples/authz"
+
+// startSpan starts a span named "fga.<op>" carrying the store ID and attrs.
+// Only identifiers are recorded; tuple payloads such as contextual tuples
+// and condition context never become attributes.
+func (c *Client) startSpan(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("fga.store_id", c.StoreID))
+	return c.tracer.Start(ctx, "fga."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// endSpan records err, if any, and ends span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// setSpanModel records the model a query was evaluated against.
+func setSpanModel(span trace.Span, modelID *string) {
+	if modelID != nil {
+		span.SetAttributes(attribute.String("fga.model_id", *modelID))
+	}
+}
+
+func relationAttr(relation string) attribute.KeyValue {
+	return attribute.String("fga.relation", relation)
+}
+
+// objectTypeAttr records only the type of object, not its ID.
+func objectTypeAttr(object string) attribute.KeyValue {
+	typ, _, _ := strings.Cut(object, ":")
+	return attribute.String("fga.object_type", typ)
+}
@@ -0,0 +1,62 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openfga/go-sdk/client"
+)
+
+// TupleBuilder builds a tuple key in reading order:
+//
+//	authz.Tuple("user:alice").Is("admin").Of("organization:acme")
+type TupleBuilder struct {
+	user, relation string
+}
+
+// Tuple starts a tuple for user, which may be an object, a wildcard or a
+// userset; see ParseUser.
+func Tuple(user string) TupleBuilder {
+	return TupleBuilder{user: user}
+}
+
+// Is sets the relation.
+func (b TupleBuilder) Is(relation string) TupleBuilder {
+	b.relation = relation
+	return b
+}
+
+// Of completes the tuple with object. It panics if the tuple is malformed,
+// so use it for literals in code; use TupleE for input that may be invalid.
+func (b TupleBuilder) Of(object string) client.ClientTupleKey {
+	tk, err := TupleE(b.user, b.relation, object)
+	if err != nil {
+		panic(err)
+	}
+	return tk
+}
+
+// TupleE builds a tuple key, reporting a malformed user, relation or object
+// as an error rather than a panic.
+func TupleE(user, relation, object string) (client.ClientTupleKey, error) {
+	if _, err := ParseUser(user); err != nil {
+		return client.ClientTupleKey{}, fmt.Errorf("authz: tuple: %w", err)
+	}
+	if relation == "" || strings.ContainsAny(relation, ":#@ ") {
+		return client.ClientTupleKey{}, fmt.Errorf("authz: tuple: invalid relation %q", relation)
+	}
+	if _, _, err := splitObject(object); err != nil {
+		return client.ClientTupleKey{}, fmt.Errorf("authz: tuple: %w", err)
+	}
+	return client.ClientTupleKey{User: user, Relation: relation, Object: object}, nil
+}
+
+// Tuples collects tuple keys into a slice, for passing to Grant:
+//
+//	fga.Grant(ctx, authz.Tuples(
+//		authz.Tuple("user:alice").Is("admin").Of("organization:acme"),
+//		authz.Tuple("user:bob").Is("member").Of("organization:acme"),
+//	)...)
+func Tuples(tuples ...client.ClientTupleKey) []client.ClientTupleKey {
+	return tuples
+}
@@ -0,0 +1,399 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrResultTruncated is returned alongside partial results when a listing
+// stops early because it reached a caller-supplied limit, or, for
+// ListObjects, the server's result limit.
+var ErrResultTruncated = errors.New("authz: result truncated")
+
+// ConditionalTuple builds a tuple that only applies while the named model
+// condition holds. Context carries the values persisted with the tuple, e.g.
+// {"expiry": "2025-01-01T00:00:00Z"} for a time-bound grant; the remaining
+// parameters are supplied at check time via WithContext.
+func ConditionalTuple(user, relation, object, condition string, context map[string]interface{}) client.ClientTupleKey {
+	cond := openfga.RelationshipCondition{Name: condition}
+	if context != nil {
+		cond.Context = &context
+	}
+	return client.ClientTupleKey{
+		User:      user,
+		Relation:  relation,
+		Object:    object,
+		Condition: &cond,
+	}
+}
+
+// Grant writes the given relationship tuples, splitting them into chunks of
+// MaxTuplesPerWrite. Tuples may carry a condition; see ConditionalTuple.
+// Use WriteTuplesBatched for a receipt of what was written, at the cost of
+// a Read per chunk. In dry-run mode nothing is written and the error, a
+// *DryRunError, matches ErrDryRun.
+func (c *Client) Grant(ctx context.Context, tuples ...client.ClientTupleKey) error {
+	receipt, err := c.writeBatched(ctx, tuples, BatchWriteOptions{}, false)
+	if err != nil || !receipt.DryRun {
+		return err
+	}
+	reqs := make([]client.ClientWriteRequest, len(receipt.Chunks))
+	for i, ch := range receipt.Chunks {
+		reqs[i] = client.ClientWriteRequest{Writes: ch.Tuples}
+	}
+	return c.dryRunError("", reqs...)
+}
+
+// TransactionLimitError is returned by GrantAtomic for a set of tuples
+// that does not fit in one transactional Write.
+type TransactionLimitError struct {
+	Size  int
+	Limit int
+}
+
+func (e *TransactionLimitError) Error() string {
+	return fmt.Sprintf("authz: %d tuples exceed the limit of %d per atomic write", e.Size, e.Limit)
+}
+
+// GrantAtomic writes tuples in a single transactional request, so either
+// all of them are stored or, on any error, none are and the call can be
+// retried as is. Unlike Grant it never splits the set: more than
+// MaxTuplesPerWrite distinct tuples are refused with a *TransactionLimitError
+// before anything is sent. In dry-run mode it reports ErrDryRun, as Grant
+// does.
+func (c *Client) GrantAtomic(ctx context.Context, tuples []client.ClientTupleKey) error {
+	req, err := dedupeWrite(client.ClientWriteRequest{Writes: tuples})
+	if err != nil {
+		return c.redactErr(err, writeValues(req)...)
+	}
+	if len(req.Writes) > MaxTuplesPerWrite {
+		return &TransactionLimitError{Size: len(req.Writes), Limit: MaxTuplesPerWrite}
+	}
+	if err := c.validateWrites(ctx, tuples, ""); err != nil {
+		return err
+	}
+	if err := c.write(ctx, req, ""); err != nil || !c.dryRun {
+		return err
+	}
+	return c.dryRunError("", req)
+}
+
+// WriteOptions configures Write and Revoke.
+type WriteOptions struct {
+	// IgnoreMissing skips deletes for tuples that are not stored instead of
+	// failing the whole request. Each delete costs an extra Read.
+	IgnoreMissing bool
+	// ModelID validates the request against this authorization model
+	// instead of the client's pinned one, e.g. a model written moments ago
+	// during a migration. When neither is set the server validates against
+	// whichever model is latest when the request arrives, which a
+	// concurrent model write can change. The model must exist in the store.
+	ModelID string
+}
+
+// Write applies writes and deletes in a single transactional request, so
+// either every change is applied or none is. Repeated tuples are sent once;
+// see dedupeWrite. In dry-run mode the request is not sent: the error then
+// matches ErrDryRun and, as a *DryRunError, carries the request.
+func (c *Client) Write(ctx context.Context, req client.ClientWriteRequest, opts WriteOptions) error {
+	if err := c.validateWrites(ctx, req.Writes, opts.ModelID); err != nil {
+		return err
+	}
+	req, err := dedupeWrite(req)
+	if err != nil {
+		return c.redactErr(err, writeValues(req)...)
+	}
+	if opts.IgnoreMissing && len(req.Deletes) > 0 {
+		deletes := make([]client.ClientTupleKeyWithoutCondition, 0, len(req.Deletes))
+		for _, tk := range req.Deletes {
+			ok, err := c.HasTuple(ctx, tk)
+			if err != nil {
+				return err
+			}
+			if ok {
+				deletes = append(deletes, tk)
+			}
+		}
+		req.Deletes = deletes
+	}
+	if err := c.write(ctx, req, opts.ModelID); err != nil || !c.dryRun {
+		return err
+	}
+	return c.dryRunError(opts.ModelID, req)
+}
+
+// dedupeWrite drops repeated tuples from req's writes and deletes, keeping
+// the first occurrence of each in order, since the server rejects a request
+// that names a tuple twice. Writes are compared including their condition.
+// A tuple that is both written and deleted, or written twice with different
+// conditions, is an error: which change wins would be up to the server.
+func dedupeWrite(req client.ClientWriteRequest) (client.ClientWriteRequest, error) {
+	written := make(map[string]string, len(req.Writes))
+	writes := make([]client.ClientTupleKey, 0, len(req.Writes))
+	for _, tk := range req.Writes {
+		k := tk.Object + "#" + tk.Relation + "@" + tk.User
+		cond, err := conditionKey(tk.Condition)
+		if err != nil {
+			return req, fmt.Errorf("authz: tuple %s: %w", k, err)
+		}
+		if prev, dup := written[k]; dup {
+			if prev != cond {
+				return req, fmt.Errorf("authz: tuple %s is written twice with different conditions", k)
+			}
+			continue
+		}
+		written[k] = cond
+		writes = append(writes, tk)
+	}
+
+	deleted := make(map[string]bool, len(req.Deletes))
+	deletes := make([]client.ClientTupleKeyWithoutCondition, 0, len(req.Deletes))
+	for _, tk := range req.Deletes {
+		k := tk.Object + "#" + tk.Relation + "@" + tk.User
+		if _, conflict := written[k]; conflict {
+			return req, fmt.Errorf("authz: tuple %s is both written and deleted", k)
+		}
+		if deleted[k] {
+			continue
+		}
+		deleted[k] = true
+		deletes = append(deletes, tk)
+	}
+	req.Writes, req.Deletes = writes, deletes
+	return req, nil
+}
+
+// conditionKey encodes a tuple condition for comparison; JSON sorts the
+// context's keys.
+func conditionKey(cond *openfga.RelationshipCondition) (string, error) {
+	if cond == nil {
+		return "", nil
+	}
+	b, err := json.Marshal(cond)
+	return string(b), err
+}
+
+// write sends req as is, against modelID or, if empty, the pinned model.
+func (c *Client) write(ctx context.Context, req client.ClientWriteRequest, modelID string) (err error) {
+	if len(req.Writes) == 0 && len(req.Deletes) == 0 {
+		return nil
+	}
+	ctx, span := c.startSpan(ctx, "Write",
+		attribute.Int("fga.writes", len(req.Writes)),
+		attribute.Int("fga.deletes", len(req.Deletes)))
+	defer func() { endSpan(span, err) }()
+	model := c.writeModel(modelID)
+	setSpanModel(span, model)
+
+	if c.dryRun {
+		c.logDryRun(req, model)
+		return nil
+	}
+	err = c.call(ctx, "Write", func(ctx context.Context) error {
+		_, err := c.fga.Write(ctx, req, client.ClientWriteOptions{
+			AuthorizationModelId: model,
+			StoreId:              &c.StoreID,
+		})
+		return c.redactErr(err, writeValues(req)...)
+	})
+	if err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	if c.cache != nil {
+		objects := make(map[string]bool, len(req.Writes)+len(req.Deletes))
+		for _, tk := range req.Writes {
+			objects[tk.Object] = true
+		}
+		for _, tk := range req.Deletes {
+			objects[tk.Object] = true
+		}
+		c.cache.invalidate(c.StoreID, objects)
+	}
+	return nil
+}
+
+// writeModel returns the model a write is validated against: modelID or,
+// if empty, the pinned model.
+func (c *Client) writeModel(modelID string) *string {
+	if modelID != "" {
+		return &modelID
+	}
+	return c.pinnedModel()
+}
+
+// writeBody returns the body of the Write request req is sent as, with
+// users and objects passed through redact if it is not nil.
+func writeBody(req client.ClientWriteRequest, modelID *string, redact func(string) string) openfga.WriteRequest {
+	if redact == nil {
+		redact = func(s string) string { return s }
+	}
+	body := openfga.WriteRequest{AuthorizationModelId: modelID}
+	if len(req.Writes) > 0 {
+		writes := make([]client.ClientTupleKey, len(req.Writes))
+		for i, tk := range req.Writes {
+			tk.User, tk.Object = redact(tk.User), redact(tk.Object)
+			writes[i] = tk
+		}
+		body.Writes = &openfga.WriteRequestWrites{TupleKeys: writes}
+	}
+	if len(req.Deletes) > 0 {
+		deletes := make([]client.ClientTupleKeyWithoutCondition, len(req.Deletes))
+		for i, tk := range req.Deletes {
+			tk.User, tk.Object = redact(tk.User), redact(tk.Object)
+			deletes[i] = tk
+		}
+		body.Deletes = &openfga.WriteRequestDeletes{TupleKeys: deletes}
+	}
+	return body
+}
+
+// logDryRun logs the body of the Write request that req would have sent,
+// with users and objects passed through the client's Redactor.
+func (c *Client) logDryRun(req client.ClientWriteRequest, modelID *string) {
+	b, err := json.Marshal(writeBody(req, modelID, c.redactValue))
+	if err != nil {
+		c.logger().Error("dry run: encode write request", "error", err)
+		return
+	}
+	c.logger().Info("dry run: write not sent", "operation", "Write",
+		"writes", len(req.Writes), "deletes", len(req.Deletes), "request", string(b))
+}
+
+// ErrDryRun is matched by the errors Grant, GrantAtomic, Write and Revoke
+// return in dry-run mode, so that a skipped write is not mistaken for a
+// stored one.
+var ErrDryRun = errors.New("authz: dry run: write not sent")
+
+// DryRunError reports tuple writes skipped in dry-run mode. It matches
+// ErrDryRun.
+type DryRunError struct {
+	// Requests are the bodies of the Write requests that would have been
+	// sent, in order, unredacted.
+	Requests []openfga.WriteRequest
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("%v: %d write requests", ErrDryRun, len(e.Requests))
+}
+
+func (e *DryRunError) Is(target error) bool {
+	return target == ErrDryRun
+}
+
+// dryRunError returns the *DryRunError for reqs, written against modelID
+// or the pinned model, or nil if none of them would have been sent.
+func (c *Client) dryRunError(modelID string, reqs ...client.ClientWriteRequest) error {
+	model := c.writeModel(modelID)
+	e := &DryRunError{}
+	for _, req := range reqs {
+		if len(req.Writes) > 0 || len(req.Deletes) > 0 {
+			e.Requests = append(e.Requests, writeBody(req, model, nil))
+		}
+	}
+	if len(e.Requests) == 0 {
+		return nil
+	}
+	return e
+}
+
+// DryRun reports whether the client was configured with Config.DryRun, in
+// which case tuple writes are logged rather than sent.
+func (c *Client) DryRun() bool {
+	return c.dryRun
+}
+
+// Revoke deletes the given tuples in a single request. OpenFGA rejects the
+// delete of a tuple that is not stored; set opts.IgnoreMissing to skip those.
+// In dry-run mode nothing is deleted and the error matches ErrDryRun.
+func (c *Client) Revoke(ctx context.Context, tuples []client.ClientTupleKeyWithoutCondition, opts WriteOptions) error {
+	return c.Write(ctx, client.ClientWriteRequest{Deletes: tuples}, opts)
+}
+
+// HasTuple reports whether the exact tuple is stored, with or without a
+// condition. Unlike Check it ignores the model: access derived from other
+// tuples through computed relations, usersets or wildcards does not count,
+// and a stored tuple counts even if its condition would not hold.
+func (c *Client) HasTuple(ctx context.Context, tk client.ClientTupleKeyWithoutCondition) (bool, error) {
+	// The Read filter treats empty fields as wildcards, so an incomplete key
+	// would match other tuples.
+	if tk.User == "" || tk.Relation == "" || tk.Object == "" {
+		return false, fmt.Errorf("authz: has tuple: user, relation and object are all required")
+	}
+	tuples, err := c.ReadTuples(ctx, client.ClientReadRequest{
+		User:     &tk.User,
+		Relation: &tk.Relation,
+		Object:   &tk.Object,
+	}, 1)
+	if err != nil && !errors.Is(err, ErrResultTruncated) {
+		return false, err
+	}
+	return len(tuples) > 0, nil
+}
+
+// ReadTuples returns the stored tuples matching filter, following
+// continuation tokens until every page has been read. A nil field in filter
+// matches anything; the zero filter reads every tuple in the store.
+//
+// If maxTuples is positive, reading stops once that many tuples have been
+// collected; if more remain, the first maxTuples are returned together with
+// ErrResultTruncated.
+func (c *Client) ReadTuples(ctx context.Context, filter client.ClientReadRequest, maxTuples int) ([]openfga.Tuple, error) {
+	var (
+		tuples    []openfga.Tuple
+		truncated bool
+	)
+	err := c.readPages(ctx, filter, func(page []openfga.Tuple, last bool) bool {
+		tuples = append(tuples, page...)
+		if maxTuples > 0 && len(tuples) >= maxTuples {
+			truncated = len(tuples) > maxTuples || !last
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		return tuples[:maxTuples], ErrResultTruncated
+	}
+	return tuples, nil
+}
+
+// readPages calls fn with each page of tuples matching filter until fn
+// returns false or the last page has been delivered.
+func (c *Client) readPages(ctx context.Context, filter client.ClientReadRequest, fn func(page []openfga.Tuple, last bool) bool) (err error) {
+	var attrs []attribute.KeyValue
+	if filter.Relation != nil {
+		attrs = append(attrs, relationAttr(*filter.Relation))
+	}
+	if filter.Object != nil {
+		attrs = append(attrs, objectTypeAttr(*filter.Object))
+	}
+	ctx, span := c.startSpan(ctx, "Read", attrs...)
+	defer func() { endSpan(span, err) }()
+
+	var token string
+	for {
+		var resp *client.ClientReadResponse
+		err := c.call(ctx, "Read", func(ctx context.Context) (err error) {
+			resp, err = c.fga.Read(ctx, filter, client.ClientReadOptions{
+				ContinuationToken: optional(token),
+				StoreId:           &c.StoreID,
+			})
+			return c.redactErr(err, deref(filter.User), deref(filter.Object))
+		})
+		if err != nil {
+			return fmt.Errorf("read tuples: %w", err)
+		}
+		token = resp.ContinuationToken
+		if !fn(resp.Tuples, token == "") || token == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+func TestDryRun(t *testing.T) {
+	const modelID = "01HVMMBCMGZNT3SED4Z17ECXCB"
+	ctx := context.Background()
+	stub := &writeReadStub{}
+	c := NewWithFGA(stub, Config{StoreID: "01HVMMBCMGZNT3SED4Z17ECXCA", ModelID: modelID, DryRun: true})
+	defer c.Close()
+
+	grant := client.ClientTupleKey{User: "user:anne", Relation: "viewer", Object: "document:plan"}
+	revoke := client.ClientTupleKeyWithoutCondition{User: "user:bob", Relation: "viewer", Object: "document:plan"}
+	model := modelID
+	wantGrant := openfga.WriteRequest{
+		Writes:               &openfga.WriteRequestWrites{TupleKeys: []client.ClientTupleKey{grant}},
+		AuthorizationModelId: &model,
+	}
+	wantRevoke := openfga.WriteRequest{
+		Deletes:              &openfga.WriteRequestDeletes{TupleKeys: []client.ClientTupleKeyWithoutCondition{revoke}},
+		AuthorizationModelId: &model,
+	}
+	tests := []struct {
+		name  string
+		write func() error
+		want  openfga.WriteRequest
+	}{
+		{"Grant", func() error { return c.Grant(ctx, grant) }, wantGrant},
+		{"GrantAtomic", func() error { return c.GrantAtomic(ctx, []client.ClientTupleKey{grant}) }, wantGrant},
+		{"Write", func() error {
+			return c.Write(ctx, client.ClientWriteRequest{Writes: []client.ClientTupleKey{grant}}, WriteOptions{})
+		}, wantGrant},
+		{"Revoke", func() error { return c.Revoke(ctx, []client.ClientTupleKeyWithoutCondition{revoke}, WriteOptions{}) }, wantRevoke},
+	}
+	for _, tt := range tests {
+		err := tt.write()
+		if !errors.Is(err, ErrDryRun) {
+			t.Errorf("%s: %v, want ErrDryRun", tt.name, err)
+			continue
+		}
+		var dr *DryRunError
+		if !errors.As(err, &dr) || !reflect.DeepEqual(dr.Requests, []openfga.WriteRequest{tt.want}) {
+			t.Errorf("%s: dry-run requests = %+v, want %+v", tt.name, dr, tt.want)
+		}
+	}
+	if stub.writes != 0 {
+		t.Errorf("dry run sent %d writes, want 0", stub.writes)
+	}
+
+	// The batched write reports dry runs on its receipt instead.
+	receipt, err := c.WriteTuplesBatched(ctx, []client.ClientTupleKey{grant}, BatchWriteOptions{})
+	if err != nil || !receipt.DryRun {
+		t.Errorf("WriteTuplesBatched = %+v, %v; want a dry-run receipt", receipt, err)
+	}
+}
@@ -0,0 +1,140 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// UserKind distinguishes the three forms a tuple's user can take.
+type UserKind int
+
+const (
+	// UserObject is a concrete object, e.g. "user:alice".
+	UserObject UserKind = iota
+	// UserWildcard is every object of a type, e.g. "user:*".
+	UserWildcard
+	// UserSet is everyone holding a relation on an object, e.g.
+	// "organization:acme#member".
+	UserSet
+)
+
+// User is a parsed tuple user. ID is "*" for a wildcard; Relation is set
+// only for a userset.
+type User struct {
+	Type     string
+	ID       string
+	Relation string
+}
+
+// ParseUser parses "type:id", "type:*" or "type:id#relation".
+func ParseUser(s string) (User, error) {
+	typ, rest, ok := strings.Cut(s, ":")
+	if !ok || !isIdentifier(typ) || rest == "" {
+		return User{}, fmt.Errorf("invalid user %q: expected type:id, type:* or type:id#relation", s)
+	}
+	id, rel, isSet := strings.Cut(rest, "#")
+	switch {
+	case id == "" || strings.ContainsAny(id, " \t"):
+		return User{}, fmt.Errorf("invalid user %q: empty or malformed id", s)
+	case isSet && id == "*":
+		return User{}, fmt.Errorf("invalid user %q: a wildcard cannot be a userset", s)
+	case isSet && !isIdentifier(rel):
+		return User{}, fmt.Errorf("invalid user %q: invalid relation %q", s, rel)
+	}
+	return User{Type: typ, ID: id, Relation: rel}, nil
+}
+
+// Kind reports which form u takes.
+func (u User) Kind() UserKind {
+	switch {
+	case u.Relation != "":
+		return UserSet
+	case u.ID == "*":
+		return UserWildcard
+	}
+	return UserObject
+}
+
+// String returns u in the form ParseUser accepts.
+func (u User) String() string {
+	s := u.Type + ":" + u.ID
+	if u.Relation != "" {
+		s += "#" + u.Relation
+	}
+	return s
+}
+
+// restriction returns the type restriction that admits u, in the DSL form
+// used by refKey: "user", "user:*" or "group#member".
+func (u User) restriction() string {
+	switch u.Kind() {
+	case UserWildcard:
+		return u.Type + ":*"
+	case UserSet:
+		return u.Type + "#" + u.Relation
+	}
+	return u.Type
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying user, e.g. the authenticated
+// subject set once by middleware. Check, CheckWithTrace, ListObjects and
+// StreamListObjects use it when called with an empty user; a non-empty
+// user argument always wins, for service-to-service calls.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user attached by WithUser.
+func UserFromContext(ctx context.Context) (string, bool) {
+	u, ok := ctx.Value(userKey{}).(string)
+	return u, ok && u != ""
+}
+
+// userOrContext returns user, or the context's user if user is empty.
+func userOrContext(ctx context.Context, user string) string {
+	if user == "" {
+		user, _ = UserFromContext(ctx)
+	}
+	return user
+}
+
+type impersonationKey struct{}
+
+type impersonation struct{ actor, target string }
+
+// WithImpersonation returns a copy of ctx in which actor acts on behalf of
+// target: like WithUser(ctx, target), queries with an empty user run as
+// target, while ActorFromContext reports actor for audit records.
+// AllowImpersonation sets it once the impersonation itself is authorized;
+// code that calls it directly takes on that check.
+func WithImpersonation(ctx context.Context, actor, target string) context.Context {
+	return context.WithValue(WithUser(ctx, target), impersonationKey{}, impersonation{actor: actor, target: target})
+}
+
+// ActorFromContext returns the real user behind the user attached to ctx
+// when that user is being impersonated through WithImpersonation.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	user, _ := UserFromContext(ctx)
+	return actorFor(ctx, user)
+}
+
+// actorFor returns the actor impersonating user in ctx, if any.
+func actorFor(ctx context.Context, user string) (string, bool) {
+	imp, ok := ctx.Value(impersonationKey{}).(impersonation)
+	if !ok || user == "" || imp.target != user {
+		return "", false
+	}
+	return imp.actor, true
+}
+
+// impersonatedBy returns the user actor acts on behalf of in ctx, if any.
+func impersonatedBy(ctx context.Context, actor string) (string, bool) {
+	imp, ok := ctx.Value(impersonationKey{}).(impersonation)
+	if !ok || actor == "" || imp.actor != actor {
+		return "", false
+	}
+	return imp.target, true
+}
@@ -0,0 +1,84 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// ListUsersResult groups the principals returned by ListUsers by kind.
+type ListUsersResult struct {
+	// Users are concrete objects, e.g. "user:alice".
+	Users []string
+	// Usersets are groups of users, e.g. "organization:acme#member".
+	Usersets []string
+	// Wildcards are public grants, e.g. "user:*".
+	Wildcards []string
+}
+
+// ListUsers returns who holds relation on object. Each entry in userTypes
+// restricts the result to a user type ("user") or a userset type
+// ("organization#member"); at least one is required by the server.
+func (c *Client) ListUsers(ctx context.Context, object, relation string, userTypes []string, opts ...QueryOption) (ListUsersResult, error) {
+	objType, objID, err := splitObject(object)
+	if err != nil {
+		return ListUsersResult{}, c.redactErr(err, object)
+	}
+	filters := make([]openfga.UserTypeFilter, 0, len(userTypes))
+	for _, ut := range userTypes {
+		f := openfga.UserTypeFilter{Type: ut}
+		if typ, rel, ok := strings.Cut(ut, "#"); ok {
+			f = openfga.UserTypeFilter{Type: typ, Relation: &rel}
+		}
+		filters = append(filters, f)
+	}
+
+	p := newQueryParams(opts)
+	modelID, err := c.queryModel(ctx, p)
+	if err != nil {
+		return ListUsersResult{}, err
+	}
+	var resp *client.ClientListUsersResponse
+	err = c.call(ctx, "ListUsers", func(ctx context.Context) (err error) {
+		resp, err = c.fga.ListUsers(ctx, client.ClientListUsersRequest{
+			Object:           openfga.FgaObject{Type: objType, Id: objID},
+			Relation:         relation,
+			UserFilters:      filters,
+			Context:          p.contextPtr(),
+			ContextualTuples: p.contextualTuples,
+		}, client.ClientListUsersOptions{
+			AuthorizationModelId: modelID,
+			StoreId:              &c.StoreID,
+			Consistency:          p.consistency,
+		})
+		return c.redactErr(err, queryValues(p.contextualTuples, object)...)
+	})
+	if err != nil {
+		return ListUsersResult{}, fmt.Errorf("list users: %w", err)
+	}
+
+	var res ListUsersResult
+	for _, u := range resp.Users {
+		switch {
+		case u.Object != nil:
+			res.Users = append(res.Users, u.Object.Type+":"+u.Object.Id)
+		case u.Userset != nil:
+			res.Usersets = append(res.Usersets, u.Userset.Type+":"+u.Userset.Id+"#"+u.Userset.Relation)
+		case u.Wildcard != nil:
+			res.Wildcards = append(res.Wildcards, u.Wildcard.Type+":*")
+		}
+	}
+	return res, nil
+}
+
+// splitObject splits "type:id" into its parts.
+func splitObject(object string) (string, string, error) {
+	typ, id, ok := strings.Cut(object, ":")
+	if !ok || typ == "" || id == "" {
+		return "", "", fmt.Errorf("invalid object %q: expected type:id", object)
+	}
+	return typ, id, nil
+}
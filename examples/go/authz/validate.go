@@ -0,0 +1,321 @@
+package authz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// validateTypeDefs checks that every type and relation referenced from a
+// rewrite or type restriction is defined, that a relation has type
+// restrictions exactly when its rewrite has one direct assignment (This),
+// and that the tupleset of each "relation from tupleset" is a direct
+// assignment of plain object types, as the server requires. Types are
+// checked in order and relations alphabetically, and the first problem is
+// reported.
+func validateTypeDefs(tds []openfga.TypeDefinition) error {
+	idx := indexTypeDefs(tds)
+	for _, td := range tds {
+		for _, name := range relationNames(td) {
+			if err := validateRelation(idx, td.Type, name); err != nil {
+				return fmt.Errorf("type %q relation %q: %w", td.Type, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateRelation(idx typeIndex, typ, relation string) error {
+	refs := idx.directTypes(typ, relation)
+	switch n := thisCount(idx[typ].relations[relation]); {
+	case n > 1:
+		return fmt.Errorf("more than one direct assignment")
+	case n == 1 && len(refs) == 0:
+		return fmt.Errorf("direct assignment without type restrictions")
+	case n == 0 && len(refs) > 0:
+		return fmt.Errorf("type restrictions %s without a direct assignment in the rewrite", refList(refs))
+	}
+	for _, ref := range refs {
+		if !idx.hasType(ref.Type) {
+			return fmt.Errorf("type restriction %q references undefined type %q", refKey(ref), ref.Type)
+		}
+		if ref.Relation != nil && !idx.hasRelation(ref.Type, *ref.Relation) {
+			return fmt.Errorf("type restriction %q references undefined relation %q on type %q", refKey(ref), *ref.Relation, ref.Type)
+		}
+	}
+	return validateUserset(idx, typ, idx[typ].relations[relation])
+}
+
+func validateUserset(idx typeIndex, typ string, us openfga.Userset) error {
+	switch {
+	case us.ComputedUserset != nil:
+		rel := us.ComputedUserset.GetRelation()
+		if !idx.hasRelation(typ, rel) {
+			return fmt.Errorf("references undefined relation %q", rel)
+		}
+	case us.TupleToUserset != nil:
+		tupleset := us.TupleToUserset.Tupleset.GetRelation()
+		rel := us.TupleToUserset.ComputedUserset.GetRelation()
+		if !idx.hasRelation(typ, tupleset) {
+			return fmt.Errorf("%q from %q: undefined relation %q", rel, tupleset, tupleset)
+		}
+		if idx[typ].relations[tupleset].This == nil {
+			return fmt.Errorf("%q from %q: %q must be a direct assignment only", rel, tupleset, tupleset)
+		}
+		parents := idx.directTypes(typ, tupleset)
+		if len(parents) == 0 {
+			return fmt.Errorf("%q from %q: %q has no directly assignable types", rel, tupleset, tupleset)
+		}
+		for _, p := range parents {
+			if p.Relation != nil || p.Wildcard != nil {
+				return fmt.Errorf("%q from %q: %q may only be assigned objects, not %q", rel, tupleset, tupleset, refKey(p))
+			}
+		}
+		for _, p := range parents {
+			if idx.hasRelation(p.Type, rel) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q from %q: relation %q is not defined on any of %s", rel, tupleset, rel, refList(parents))
+	case us.Union != nil:
+		return validateChildren(idx, typ, us.Union.Child)
+	case us.Intersection != nil:
+		return validateChildren(idx, typ, us.Intersection.Child)
+	case us.Difference != nil:
+		if err := validateUserset(idx, typ, us.Difference.Base); err != nil {
+			return err
+		}
+		return validateUserset(idx, typ, us.Difference.Subtract)
+	}
+	return nil
+}
+
+// thisCount returns the number of direct assignments (This operands) in us.
+// In "[user] or editor" the bracketed part is the This operand of the union;
+// only it admits stored tuples, so type restrictions apply to it alone.
+func thisCount(us openfga.Userset) int {
+	switch {
+	case us.This != nil:
+		return 1
+	case us.Union != nil:
+		return childrenThisCount(us.Union.Child)
+	case us.Intersection != nil:
+		return childrenThisCount(us.Intersection.Child)
+	case us.Difference != nil:
+		return thisCount(us.Difference.Base) + thisCount(us.Difference.Subtract)
+	}
+	return 0
+}
+
+func childrenThisCount(children []openfga.Userset) int {
+	n := 0
+	for _, child := range children {
+		n += thisCount(child)
+	}
+	return n
+}
+
+func validateChildren(idx typeIndex, typ string, children []openfga.Userset) error {
+	for _, child := range children {
+		if err := validateUserset(idx, typ, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TupleProblem describes why one tuple failed validation.
+type TupleProblem struct {
+	// Index is the tuple's position in the validated slice.
+	Index  int
+	Tuple  client.ClientTupleKey
+	Reason string
+}
+
+// TupleValidationError lists every tuple that failed ValidateTuples, or
+// every assertion WriteAssertions rejected.
+type TupleValidationError struct {
+	Problems []TupleProblem
+}
+
+func (e *TupleValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "authz: %d invalid tuple(s)", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "; #%d %s#%s@%s: %s", p.Index, p.Tuple.Object, p.Tuple.Relation, p.Tuple.User, p.Reason)
+	}
+	return b.String()
+}
+
+// ValidateTuples checks each tuple against the model: the object's type must
+// exist, the relation must be defined on it, and the user must match one of
+// the relation's directly assignable types, including any condition. Only
+// the direct assignment of a rewrite counts: for "[user] or editor" a
+// tuple may assign a user, but not the users editor admits. All
+// offending tuples are reported in a single *TupleValidationError.
+func ValidateTuples(typeDefs []openfga.TypeDefinition, tuples []client.ClientTupleKey) error {
+	return indexTypeDefs(typeDefs).validateTuples(tuples)
+}
+
+func (idx typeIndex) validateTuples(tuples []client.ClientTupleKey) error {
+	var verr TupleValidationError
+	for i, tk := range tuples {
+		if reason := idx.checkTuple(tk); reason != "" {
+			verr.Problems = append(verr.Problems, TupleProblem{Index: i, Tuple: tk, Reason: reason})
+		}
+	}
+	if len(verr.Problems) > 0 {
+		return &verr
+	}
+	return nil
+}
+
+// checkTuple returns why tk is invalid, or "" if it is valid.
+func (idx typeIndex) checkTuple(tk client.ClientTupleKey) string {
+	objType, _, err := splitObject(tk.Object)
+	if err != nil {
+		return err.Error()
+	}
+	if !idx.hasType(objType) {
+		return fmt.Sprintf("type %q is not defined", objType)
+	}
+	if !idx.hasRelation(objType, tk.Relation) {
+		return fmt.Sprintf("relation %q is not defined on type %q", tk.Relation, objType)
+	}
+	allowed := idx.directTypes(objType, tk.Relation)
+	if len(allowed) == 0 || thisCount(idx[objType].relations[tk.Relation]) == 0 {
+		return fmt.Sprintf("%s#%s is not directly assignable", objType, tk.Relation)
+	}
+
+	user, err := ParseUser(tk.User)
+	if err != nil {
+		return err.Error()
+	}
+	want := user.restriction()
+	if tk.Condition != nil && tk.Condition.Name != "" {
+		want += " with " + tk.Condition.Name
+	}
+	for _, ref := range allowed {
+		if refKey(ref) == want {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%q may not be assigned %s#%s; allowed %s", want, objType, tk.Relation, refList(allowed))
+}
+
+// checkCondition returns why cond cannot be stored with a tuple under a
+// model declaring conditions, or "" if it can. The stored context may omit
+// parameters, which are then supplied at query time, but must not name
+// parameters the condition does not declare.
+func checkCondition(conditions map[string]openfga.Condition, cond *openfga.RelationshipCondition) string {
+	decl, ok := conditions[cond.Name]
+	if !ok {
+		return fmt.Sprintf("condition %q is not defined in the model", cond.Name)
+	}
+	if cond.Context == nil {
+		return ""
+	}
+	params := decl.GetParameters()
+	for _, key := range sortedKeys(*cond.Context) {
+		if _, ok := params[key]; !ok {
+			return fmt.Sprintf("condition %q has no parameter %q", cond.Name, key)
+		}
+	}
+	return ""
+}
+
+// checkQuery returns why the user, relation and object of tk cannot be
+// queried under the model, or "" if they can. Unlike checkTuple it does not
+// require the relation to be directly assignable, since a query may be
+// answered through any rewrite.
+func (idx typeIndex) checkQuery(tk client.ClientTupleKey) string {
+	objType, _, err := splitObject(tk.Object)
+	if err != nil {
+		return err.Error()
+	}
+	if !idx.hasType(objType) {
+		return fmt.Sprintf("type %q is not defined", objType)
+	}
+	if !idx.hasRelation(objType, tk.Relation) {
+		return fmt.Sprintf("relation %q is not defined on type %q", tk.Relation, objType)
+	}
+	user, err := ParseUser(tk.User)
+	if err != nil {
+		return err.Error()
+	}
+	if !idx.hasType(user.Type) {
+		return fmt.Sprintf("user type %q is not defined", user.Type)
+	}
+	if user.Relation != "" && !idx.hasRelation(user.Type, user.Relation) {
+		return fmt.Sprintf("relation %q is not defined on user type %q", user.Relation, user.Type)
+	}
+	return ""
+}
+
+// typeIndex maps type names to their relations and type restrictions.
+type typeIndex map[string]typeEntry
+
+type typeEntry struct {
+	relations map[string]openfga.Userset
+	metadata  map[string]openfga.RelationMetadata
+}
+
+func indexTypeDefs(tds []openfga.TypeDefinition) typeIndex {
+	idx := make(typeIndex, len(tds))
+	for _, td := range tds {
+		e := typeEntry{relations: map[string]openfga.Userset{}, metadata: map[string]openfga.RelationMetadata{}}
+		if td.Relations != nil {
+			e.relations = *td.Relations
+		}
+		if td.Metadata != nil && td.Metadata.Relations != nil {
+			e.metadata = *td.Metadata.Relations
+		}
+		idx[td.Type] = e
+	}
+	return idx
+}
+
+func (idx typeIndex) hasType(typ string) bool {
+	_, ok := idx[typ]
+	return ok
+}
+
+func (idx typeIndex) hasRelation(typ, relation string) bool {
+	_, ok := idx[typ].relations[relation]
+	return ok
+}
+
+func (idx typeIndex) directTypes(typ, relation string) []openfga.RelationReference {
+	md, ok := idx[typ].metadata[relation]
+	if !ok || md.DirectlyRelatedUserTypes == nil {
+		return nil
+	}
+	return *md.DirectlyRelatedUserTypes
+}
+
+// relationNames returns the relations of td in sorted order.
+func relationNames(td openfga.TypeDefinition) []string {
+	if td.Relations == nil {
+		return nil
+	}
+	names := make([]string, 0, len(*td.Relations))
+	for name := range *td.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func refList(refs []openfga.RelationReference) string {
+	s := ""
+	for i, ref := range refs {
+		if i > 0 {
+			s += ", "
+		}
+		s += refKey(ref)
+	}
+	return "[" + s + "]"
+}
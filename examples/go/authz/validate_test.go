@@ -0,0 +1,140 @@
+package authz
+
+import (
+	"strings"
+	"testing"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// relation is a relation for building type definitions in tests: its
+// rewrite and directly assignable types, written as in the DSL.
+type relation struct {
+	rewrite openfga.Userset
+	direct  []string
+}
+
+func typeDef(typ string, rels map[string]relation) openfga.TypeDefinition {
+	td := openfga.TypeDefinition{Type: typ}
+	if len(rels) == 0 {
+		return td
+	}
+	usersets := map[string]openfga.Userset{}
+	metadata := map[string]openfga.RelationMetadata{}
+	for name, r := range rels {
+		usersets[name] = r.rewrite
+		if r.direct == nil {
+			continue
+		}
+		refs := []openfga.RelationReference{}
+		for _, d := range r.direct {
+			ref, err := parseRelationReference(d)
+			if err != nil {
+				panic(err)
+			}
+			refs = append(refs, ref)
+		}
+		metadata[name] = openfga.RelationMetadata{DirectlyRelatedUserTypes: &refs}
+	}
+	td.Relations = &usersets
+	td.Metadata = &openfga.Metadata{Relations: &metadata}
+	return td
+}
+
+func this() openfga.Userset {
+	return openfga.Userset{This: &map[string]interface{}{}}
+}
+
+func union(children ...openfga.Userset) openfga.Userset {
+	return openfga.Userset{Union: &openfga.Usersets{Child: children}}
+}
+
+func butNot(base, subtract openfga.Userset) openfga.Userset {
+	return openfga.Userset{Difference: &openfga.Difference{Base: base, Subtract: subtract}}
+}
+
+func TestValidateTypeDefs(t *testing.T) {
+	user := typeDef("user", nil)
+	group := typeDef("group", map[string]relation{"member": {this(), []string{"user"}}})
+	org := typeDef("organization", map[string]relation{"admin": {this(), []string{"user"}}})
+
+	tests := []struct {
+		name string
+		tds  []openfga.TypeDefinition
+		// err is a substring of the expected error, or empty if the type
+		// definitions are valid.
+		err string
+	}{
+		{"direct", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"viewer": {this(), []string{"user", "user:*", "group#member"}},
+		}), group}, ""},
+		{"union with computed", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"editor": {this(), []string{"user"}},
+			"viewer": {union(this(), computed("editor")), []string{"user"}},
+		})}, ""},
+		{"computed only", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"editor": {this(), []string{"user"}},
+			"viewer": {computed("editor"), nil},
+		})}, ""},
+		{"but not", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"banned": {this(), []string{"user"}},
+			"viewer": {butNot(this(), computed("banned")), []string{"user"}},
+		})}, ""},
+		{"tuple to userset", []openfga.TypeDefinition{user, org, typeDef("project", map[string]relation{
+			"organization": {this(), []string{"organization"}},
+			"admin":        {union(this(), tupleToUserset("admin", "organization")), []string{"user"}},
+		})}, ""},
+
+		{"two direct assignments", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"viewer": {union(this(), this()), []string{"user"}},
+		})}, "more than one direct assignment"},
+		{"direct assignment without types", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"viewer": {this(), nil},
+		})}, "direct assignment without type restrictions"},
+		{"types without direct assignment", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"editor": {this(), []string{"user"}},
+			"viewer": {computed("editor"), []string{"user"}},
+		})}, "without a direct assignment"},
+		{"undefined type", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"viewer": {this(), []string{"team"}},
+		})}, `undefined type "team"`},
+		{"undefined userset relation", []openfga.TypeDefinition{user, group, typeDef("doc", map[string]relation{
+			"viewer": {this(), []string{"group#owner"}},
+		})}, `undefined relation "owner" on type "group"`},
+		{"undefined computed relation", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"viewer": {union(this(), computed("editor")), []string{"user"}},
+		})}, `references undefined relation "editor"`},
+		{"undefined subtracted relation", []openfga.TypeDefinition{user, typeDef("doc", map[string]relation{
+			"viewer": {butNot(this(), computed("banned")), []string{"user"}},
+		})}, `references undefined relation "banned"`},
+		{"undefined tupleset", []openfga.TypeDefinition{user, org, typeDef("project", map[string]relation{
+			"admin": {tupleToUserset("admin", "organization"), nil},
+		})}, `undefined relation "organization"`},
+		{"computed tupleset", []openfga.TypeDefinition{user, org, typeDef("project", map[string]relation{
+			"owner":        {this(), []string{"organization"}},
+			"organization": {union(this(), computed("owner")), []string{"organization"}},
+			"admin":        {tupleToUserset("admin", "organization"), nil},
+		})}, "must be a direct assignment only"},
+		{"userset tupleset", []openfga.TypeDefinition{user, group, typeDef("project", map[string]relation{
+			"parent": {this(), []string{"group#member"}},
+			"admin":  {tupleToUserset("member", "parent"), nil},
+		})}, `may only be assigned objects, not "group#member"`},
+		{"relation missing on parent", []openfga.TypeDefinition{user, org, typeDef("project", map[string]relation{
+			"organization": {this(), []string{"organization"}},
+			"viewer":       {tupleToUserset("viewer", "organization"), nil},
+		})}, `relation "viewer" is not defined on any of [organization]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTypeDefs(tt.tds)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("validateTypeDefs: %v", err)
+			case tt.err != "" && err == nil:
+				t.Errorf("validateTypeDefs succeeded, want error containing %q", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Errorf("validateTypeDefs = %v, want error containing %q", err, tt.err)
+			}
+		})
+	}
+}
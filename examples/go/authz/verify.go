@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+)
+
+// ErrNotVerified is matched by GrantAndVerify errors when the tuple was
+// written but a Check did not see it before the timeout. The grant is not
+// rolled back and may still become visible later.
+var ErrNotVerified = errors.New("authz: grant written but not yet visible")
+
+// verifyPoll bounds the delay between GrantAndVerify's Checks.
+const (
+	verifyPollMin = 50 * time.Millisecond
+	verifyPollMax = time.Second
+)
+
+// GrantAndVerify writes tk and then polls Check with higher consistency
+// until it reports the grant or timeout elapses, for critical grants such as
+// an ownership transfer that later steps must be able to rely on. A failed
+// write is returned as is; a write that is not confirmed in time yields an
+// error matching ErrNotVerified. The Check carries no request context, so a
+// conditional grant whose condition needs request-time parameters cannot be
+// verified this way. In dry-run mode nothing is written or verified, and
+// Grant's error, matching ErrDryRun, is returned.
+func (c *Client) GrantAndVerify(ctx context.Context, tk client.ClientTupleKey, timeout time.Duration) error {
+	if err := c.Grant(ctx, tk); err != nil {
+		return err
+	}
+
+	strong := WithConsistency(openfga.CONSISTENCYPREFERENCE_HIGHER_CONSISTENCY)
+	deadline := time.Now().Add(timeout)
+	delay := verifyPollMin
+	for {
+		allowed, err := c.Check(ctx, tk.User, tk.Relation, tk.Object, strong)
+		if err != nil {
+			return fmt.Errorf("verify grant %s#%s@%s: %w", tk.Object, tk.Relation, tk.User, err)
+		}
+		if allowed {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("%w: %s#%s@%s after %s", ErrNotVerified, tk.Object, tk.Relation, tk.User, timeout)
+		}
+		select {
+		case <-time.After(min(delay, remaining)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay = min(2*delay, verifyPollMax)
+	}
+}
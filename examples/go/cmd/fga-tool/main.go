@@ -0,0 +1,365 @@
+// Command fga-tool runs ad-hoc operations against an OpenFGA server
+// through the authz package.
+//
+// Usage:
+//
+//	fga-tool [global flags] <command> [flags]
+//
+// Commands:
+//
+//	store create --name NAME
+//	store list
+//	store delete [--id ID]
+//	model write [--file MODEL.fga]
+//	tuple write --user U --relation R --object O [--condition NAME [--context JSON]]
+//	tuple delete --user U --relation R --object O
+//	tuple import --file FILE [--format csv|jsonl]
+//	check --user U --relation R --object O
+//
+// Connection settings are read from the FGA_* environment variables (see
+// authz.ConfigFromEnv) and can be overridden by the global flags. Results
+// are printed as text, or as JSON with --json. Without --file, model write
+// uses the nearest model.fga found by authz.DiscoverModelFile.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openfga/go-sdk/client"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
+)
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fga-tool: %v\n", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("usage: fga-tool [global flags] store|model|tuple|check ...; run with -h for help")
+
+// tool carries the connected client and output settings to each command.
+type tool struct {
+	fga  *authz.Client
+	json bool
+	out  io.Writer
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	cfg, err := authz.ConfigFromEnv()
+	if err != nil && !errors.Is(err, authz.ErrNoAPIURL) {
+		return err
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = "http://localhost:8080"
+	}
+
+	global := flag.NewFlagSet("fga-tool", flag.ContinueOnError)
+	global.StringVar(&cfg.APIURL, "api-url", cfg.APIURL, "OpenFGA API URL (FGA_API_URL)")
+	global.StringVar(&cfg.StoreID, "store-id", cfg.StoreID, "store ID (FGA_STORE_ID)")
+	global.StringVar(&cfg.ModelID, "model-id", cfg.ModelID, "authorization model ID (FGA_MODEL_ID)")
+	global.StringVar(&cfg.APIToken, "api-token", cfg.APIToken, "pre-shared API token (FGA_API_TOKEN)")
+	timeout := global.Duration("timeout", 30*time.Second, "timeout for each API call")
+	asJSON := global.Bool("json", false, "print results as JSON")
+	global.Usage = func() {
+		fmt.Fprintln(global.Output(), errUsage)
+		global.PrintDefaults()
+	}
+	if err := global.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if global.NArg() == 0 {
+		return errUsage
+	}
+	cfg.DefaultTimeout = *timeout
+
+	fga, err := authz.New(cfg)
+	if err != nil {
+		return err
+	}
+	defer fga.Close()
+	t := &tool{fga: fga, json: *asJSON, out: out}
+
+	cmd, rest := global.Arg(0), global.Args()[1:]
+	if cmd == "check" {
+		return t.check(ctx, rest)
+	}
+	if len(rest) == 0 {
+		return errUsage
+	}
+	sub, rest := rest[0], rest[1:]
+	switch cmd + " " + sub {
+	case "store create":
+		return t.storeCreate(ctx, rest)
+	case "store list":
+		return t.storeList(ctx, rest)
+	case "store delete":
+		return t.storeDelete(ctx, rest)
+	case "model write":
+		return t.modelWrite(ctx, rest)
+	case "tuple write":
+		return t.tupleWrite(ctx, rest)
+	case "tuple delete":
+		return t.tupleDelete(ctx, rest)
+	case "tuple import":
+		return t.tupleImport(ctx, rest)
+	}
+	return fmt.Errorf("unknown command %q: %w", cmd+" "+sub, errUsage)
+}
+
+// print writes v as JSON, or text as is.
+func (t *tool) print(v interface{}, text string) error {
+	if t.json {
+		enc := json.NewEncoder(t.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	_, err := fmt.Fprintln(t.out, text)
+	return err
+}
+
+// parse parses a command's flags, treating any positional argument as an
+// error so that misplaced flags are not silently ignored.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%s: unexpected argument %q", fs.Name(), fs.Arg(0))
+	}
+	return nil
+}
+
+// flagError passes through a request for help, which the flag package has
+// already answered, and otherwise reports a usage error.
+func flagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return errUsage
+}
+
+func required(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		if fs.Lookup(name).Value.String() == "" {
+			return fmt.Errorf("%s: --%s is required", fs.Name(), name)
+		}
+	}
+	return nil
+}
+
+func (t *tool) requireStore() error {
+	if t.fga.StoreID == "" {
+		return fmt.Errorf("no store selected: set --store-id or FGA_STORE_ID")
+	}
+	return nil
+}
+
+func (t *tool) storeCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("store create", flag.ContinueOnError)
+	name := fs.String("name", "", "store name")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := required(fs, "name"); err != nil {
+		return err
+	}
+	id, err := t.fga.CreateStore(ctx, *name)
+	if err != nil {
+		return err
+	}
+	return t.print(map[string]string{"id": id, "name": *name}, id)
+}
+
+func (t *tool) storeList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("store list", flag.ContinueOnError)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	stores, err := t.fga.ListStores(ctx)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, st := range stores {
+		fmt.Fprintf(&b, "%s\t%s\t%s\n", st.Id, st.Name, st.CreatedAt.Format(time.RFC3339))
+	}
+	return t.print(stores, strings.TrimSuffix(b.String(), "\n"))
+}
+
+func (t *tool) storeDelete(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("store delete", flag.ContinueOnError)
+	id := fs.String("id", t.fga.StoreID, "store ID; defaults to --store-id")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := required(fs, "id"); err != nil {
+		return err
+	}
+	if err := t.fga.DeleteStore(ctx, *id); err != nil {
+		return err
+	}
+	return t.print(map[string]string{"deleted": *id}, "deleted "+*id)
+}
+
+func (t *tool) modelWrite(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("model write", flag.ContinueOnError)
+	file := fs.String("file", "", "model in the OpenFGA DSL (.fga); defaults to the nearest model.fga or authorization-model.fga")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := t.requireStore(); err != nil {
+		return err
+	}
+	if *file == "" {
+		path, err := authz.DiscoverModelFile("")
+		if err != nil {
+			return fmt.Errorf("model write: %w; pass --file", err)
+		}
+		*file = path
+	}
+	id, err := t.fga.WriteModelFromFile(ctx, *file)
+	if err != nil {
+		return err
+	}
+	return t.print(map[string]string{"authorization_model_id": id}, id)
+}
+
+// tupleFlags registers the flags naming a single tuple.
+func tupleFlags(fs *flag.FlagSet) (user, relation, object *string) {
+	return fs.String("user", "", "user, e.g. user:alice or group:eng#member"),
+		fs.String("relation", "", "relation"),
+		fs.String("object", "", "object, e.g. document:roadmap")
+}
+
+func (t *tool) tupleWrite(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tuple write", flag.ContinueOnError)
+	user, relation, object := tupleFlags(fs)
+	condition := fs.String("condition", "", "condition name")
+	condContext := fs.String("context", "", "condition context as a JSON object")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := required(fs, "user", "relation", "object"); err != nil {
+		return err
+	}
+	if err := t.requireStore(); err != nil {
+		return err
+	}
+	tk := client.ClientTupleKey{User: *user, Relation: *relation, Object: *object}
+	if *condition != "" {
+		var values map[string]interface{}
+		if *condContext != "" {
+			if err := json.Unmarshal([]byte(*condContext), &values); err != nil {
+				return fmt.Errorf("tuple write: --context: %w", err)
+			}
+		}
+		tk = authz.ConditionalTuple(*user, *relation, *object, *condition, values)
+	} else if *condContext != "" {
+		return fmt.Errorf("tuple write: --context requires --condition")
+	}
+	if err := t.fga.Grant(ctx, tk); err != nil {
+		return err
+	}
+	return t.print(tk, "wrote "+*object+"#"+*relation+"@"+*user)
+}
+
+func (t *tool) tupleDelete(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tuple delete", flag.ContinueOnError)
+	user, relation, object := tupleFlags(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := required(fs, "user", "relation", "object"); err != nil {
+		return err
+	}
+	if err := t.requireStore(); err != nil {
+		return err
+	}
+	tk := client.ClientTupleKeyWithoutCondition{User: *user, Relation: *relation, Object: *object}
+	if err := t.fga.Revoke(ctx, []client.ClientTupleKeyWithoutCondition{tk}, authz.WriteOptions{}); err != nil {
+		return err
+	}
+	return t.print(tk, "deleted "+*object+"#"+*relation+"@"+*user)
+}
+
+func (t *tool) tupleImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tuple import", flag.ContinueOnError)
+	file := fs.String("file", "", "tuples to import; - reads standard input")
+	format := fs.String("format", "", "csv or jsonl; defaults from the file extension")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := required(fs, "file"); err != nil {
+		return err
+	}
+	if err := t.requireStore(); err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = "csv"
+		if strings.HasSuffix(*file, ".jsonl") || strings.HasSuffix(*file, ".ndjson") {
+			*format = "jsonl"
+		}
+	}
+	var f authz.ImportFormat
+	switch *format {
+	case "csv":
+		f = authz.ImportCSV
+	case "jsonl":
+		f = authz.ImportJSONLines
+	default:
+		return fmt.Errorf("tuple import: unknown format %q", *format)
+	}
+
+	r := io.Reader(os.Stdin)
+	if *file != "-" {
+		fh, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		r = fh
+	}
+	res, err := t.fga.ImportTuples(ctx, r, f)
+	if err != nil {
+		return err
+	}
+	return t.print(map[string]int{"written": res.Written, "duplicates": res.Duplicates},
+		fmt.Sprintf("wrote %d tuples, skipped %d duplicates", res.Written, res.Duplicates))
+}
+
+func (t *tool) check(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	user, relation, object := tupleFlags(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := required(fs, "user", "relation", "object"); err != nil {
+		return err
+	}
+	if err := t.requireStore(); err != nil {
+		return err
+	}
+	allowed, err := t.fga.Check(ctx, *user, *relation, *object)
+	if err != nil {
+		return err
+	}
+	return t.print(map[string]bool{"allowed": allowed}, fmt.Sprint(allowed))
+}
@@ -1,172 +1,101 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	_ "embed"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"time"
 
 	openfga "github.com/openfga/go-sdk"
 	"github.com/openfga/go-sdk/client"
+
+	"github.com/bogdanticu88/openfga-examples/authz"
 )
 
 func main() {
 	ctx := context.Background()
 
-	// Store ID is not set at construction — created dynamically below.
-	fgaClient, err := client.NewSdkClient(&client.ClientConfiguration{
-		ApiUrl: "http://localhost:8080",
-	})
+	// Connection settings come from FGA_* environment variables, falling
+	// back to the local docker-compose server. Without FGA_STORE_ID the
+	// store is looked up by name and created on first run.
+	cfg, err := authz.ConfigFromEnv()
+	if errors.Is(err, authz.ErrNoAPIURL) {
+		cfg.APIURL = "http://localhost:8080"
+	} else if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	// Fail instead of hanging if the server stops responding.
+	cfg.DefaultTimeout = 10 * time.Second
+	// Surface retries and other library warnings on stderr.
+	cfg.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	fga, err := authz.New(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create OpenFGA client: %v", err)
 	}
+	defer fga.Close()
 
-	storeID := createStore(ctx, fgaClient)
-	fgaClient.SetStoreId(storeID)
-
-	modelID := createAuthorizationModel(ctx, fgaClient)
-	fgaClient.SetAuthorizationModelId(modelID)
-
-	createRelationships(ctx, fgaClient)
-	checkAccess(ctx, fgaClient)
-	listPermissions(ctx, fgaClient)
-}
-
-func createStore(ctx context.Context, fgaClient *client.OpenFgaClient) string {
-	resp, err := fgaClient.CreateStore(ctx).Body(client.ClientCreateStoreRequest{
-		Name: "authorization-store",
-	}).Execute()
-	if err != nil {
-		log.Fatalf("Failed to create store: %v", err)
+	if fga.StoreID == "" {
+		if _, err := fga.GetOrCreateStore(ctx, "authorization-store"); err != nil {
+			log.Fatalf("Failed to get or create store: %v", err)
+		}
 	}
-	fmt.Printf("Created store: %s\n", resp.Id)
-	return resp.Id
-}
+	fmt.Printf("Using store: %s\n", fga.StoreID)
 
-// createAuthorizationModel writes a minimal RBAC model (user / organization / project)
-// using TypeDefinitions. For larger models, prefer loading from a .fga file using
-// the openfga/language package and its transformer.
-func createAuthorizationModel(ctx context.Context, fgaClient *client.OpenFgaClient) string {
-	schemaVersion := "1.1"
-	thisUserset := openfga.Userset{This: &map[string]interface{}{}}
-
-	resp, err := fgaClient.WriteAuthorizationModel(ctx).Body(client.ClientWriteAuthorizationModelRequest{
-		SchemaVersion: schemaVersion,
-		TypeDefinitions: []openfga.TypeDefinition{
-			{
-				Type: "user",
-			},
-			{
-				Type: "organization",
-				Relations: &map[string]openfga.Userset{
-					"admin":  thisUserset,
-					"member": thisUserset,
-				},
-				Metadata: &openfga.Metadata{
-					Relations: &map[string]openfga.RelationMetadata{
-						"admin": {
-							DirectlyRelatedUserTypes: &[]openfga.RelationReference{
-								{Type: "user"},
-							},
-						},
-						"member": {
-							DirectlyRelatedUserTypes: &[]openfga.RelationReference{
-								{Type: "user"},
-							},
-						},
-					},
-				},
-			},
-			{
-				Type: "project",
-				Relations: &map[string]openfga.Userset{
-					"organization": thisUserset,
-					"owner":        thisUserset,
-					"editor":       thisUserset,
-					"viewer":       thisUserset,
-				},
-				Metadata: &openfga.Metadata{
-					Relations: &map[string]openfga.RelationMetadata{
-						"organization": {
-							DirectlyRelatedUserTypes: &[]openfga.RelationReference{
-								{Type: "organization"},
-							},
-						},
-						"owner": {
-							DirectlyRelatedUserTypes: &[]openfga.RelationReference{
-								{Type: "user"},
-							},
-						},
-						"editor": {
-							DirectlyRelatedUserTypes: &[]openfga.RelationReference{
-								{Type: "user"},
-							},
-						},
-						"viewer": {
-							DirectlyRelatedUserTypes: &[]openfga.RelationReference{
-								{Type: "user"},
-							},
-						},
-					},
-				},
-			},
-		},
-	}).Execute()
+	modelID, err := fga.WriteModelFromReader(ctx, bytes.NewReader(sampleModel))
 	if err != nil {
 		log.Fatalf("Failed to write authorization model: %v", err)
 	}
-	fmt.Printf("Authorization model ID: %s\n", resp.AuthorizationModelId)
-	return resp.AuthorizationModelId
-}
+	fmt.Printf("Authorization model ID: %s\n", modelID)
 
-func createRelationships(ctx context.Context, fgaClient *client.OpenFgaClient) {
-	err := fgaClient.WriteTuples(ctx).Body([]client.ClientTupleKey{
-		{
-			User:     "user:alice",
-			Relation: "admin",
-			Object:   "organization:acme",
-		},
-		{
-			User:     "user:bob",
-			Relation: "member",
-			Object:   "organization:acme",
-		},
-		{
-			User:     "organization:acme",
-			Relation: "organization",
-			Object:   "project:api",
-		},
-		{
-			User:     "user:alice",
-			Relation: "owner",
-			Object:   "project:api",
-		},
-	}).Execute()
-	if err != nil {
+	if err := fga.Grant(ctx, sampleTuples()...); err != nil {
 		log.Fatalf("Failed to write relationships: %v", err)
 	}
 	fmt.Println("Relationships created successfully")
-}
 
-func checkAccess(ctx context.Context, fgaClient *client.OpenFgaClient) {
-	resp, err := fgaClient.Check(ctx).Body(client.ClientCheckRequest{
-		User:     "user:alice",
-		Relation: "admin",
-		Object:   "organization:acme",
-	}).Execute()
+	// The tuples were written just now, so read them back with higher
+	// consistency rather than risk a stale answer from the server's cache.
+	allowed, err := fga.Check(ctx, "user:alice", "admin", "organization:acme",
+		authz.WithConsistency(openfga.CONSISTENCYPREFERENCE_HIGHER_CONSISTENCY))
 	if err != nil {
 		log.Fatalf("Failed to check access: %v", err)
 	}
-	fmt.Printf("Alice is admin of acme: %v\n", resp.Allowed)
-}
+	fmt.Printf("Alice is admin of acme: %v\n", allowed)
+
+	// project:api belongs to acme, so acme's admins inherit admin on it
+	// through "admin from organization".
+	allowed, err = fga.Check(ctx, "user:alice", "admin", "project:api",
+		authz.WithConsistency(openfga.CONSISTENCYPREFERENCE_HIGHER_CONSISTENCY))
+	if err != nil {
+		log.Fatalf("Failed to check access: %v", err)
+	}
+	fmt.Printf("Alice is admin of api (via acme): %v\n", allowed)
 
-func listPermissions(ctx context.Context, fgaClient *client.OpenFgaClient) {
-	resp, err := fgaClient.ListObjects(ctx).Body(client.ClientListObjectsRequest{
-		User:     "user:alice",
-		Relation: "admin",
-		Type:     "organization",
-	}).Execute()
+	objects, err := fga.ListObjects(ctx, "user:alice", "admin", "organization")
 	if err != nil {
 		log.Fatalf("Failed to list objects: %v", err)
 	}
-	fmt.Printf("Alice can admin: %v\n", resp.Objects)
+	fmt.Printf("Alice can admin: %v\n", objects)
+}
+
+// sampleModel is a minimal RBAC model (user / organization / project). It
+// lives in model.fga so it can also be loaded with the OpenFGA CLI.
+// Relations are listed alphabetically, matching authz.RenderDSL output.
+//
+//go:embed model.fga
+var sampleModel []byte
+
+func sampleTuples() []client.ClientTupleKey {
+	return authz.Tuples(
+		authz.Tuple("user:alice").Is("admin").Of("organization:acme"),
+		authz.Tuple("user:bob").Is("member").Of("organization:acme"),
+		authz.Tuple("organization:acme").Is("organization").Of("project:api"),
+		authz.Tuple("user:alice").Is("owner").Of("project:api"),
+		// A userset: every member of acme can view the project.
+		authz.Tuple("organization:acme#member").Is("viewer").Of("project:api"),
+	)
 }